@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// AuditContext adds an audit Logger with a Core to a Context's values, so that security events can be routed to a
+// separate sink, e.g. an append-only file or a remote collector. The audit Logger uses a distinct context key from the
+// Context logger: it is not affected by WithLogger, With, Named, or level changes, and the Context logger never writes
+// to its Core
+func AuditContext(ctx context.Context, core zapcore.Core, opts ...zap.Option) context.Context {
+	return context.WithValue(ctx, auditKey, zap.New(core, opts...))
+}
+
+// AuditLogger attempts to retrieve the audit Logger from a Context's values
+func AuditLogger(ctx context.Context) *zap.Logger {
+	if logger, is := ctx.Value(auditKey).(*zap.Logger); is {
+		return logger
+	}
+
+	return nop
+}
+
+// Audit is a helper to log a single info-level message to a Context's audit Logger, with the fields that have been
+// added to the Context logger, e.g. a request ID, followed by fields. Nothing is logged if the Context does not have an
+// audit Logger
+func Audit(ctx context.Context, msg string, fields ...zap.Field) {
+	AuditLogger(ctx).Info(msg, append(Fields(ctx), fields...)...)
+}
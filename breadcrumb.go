@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// BreadcrumbKey is the key of the field that Breadcrumb adds to entries
+const BreadcrumbKey = "breadcrumb"
+
+// Breadcrumb appends a segment to a Context's breadcrumb, e.g. "auth>token>verify", and re-injects a Logger that adds
+// it to each entry as a breadcrumb field into a child Context, for human readers of console output where the Logger's
+// name is not prominent. Nested breadcrumbs replace their parent's field instead of repeating it. Breadcrumb is
+// independent of Named: it does not change the Logger's name, and Named does not add segments
+func Breadcrumb(ctx context.Context, segment string) context.Context {
+	crumbs, _ := ctx.Value(breadcrumbKey).(string)
+	if len(crumbs) > 0 {
+		crumbs += ">" + segment
+	} else {
+		crumbs = segment
+	}
+
+	ctx, _ = WithOptions(context.WithValue(ctx, breadcrumbKey, crumbs), zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		// Replace a parent breadcrumb, so that entries do not have duplicate keys
+		if parent, is := core.(*breadcrumbCore); is {
+			core = parent.Core
+		}
+
+		return &breadcrumbCore{Core: core, crumbs: crumbs}
+	}))
+
+	return ctx
+}
+
+// breadcrumbCore adds a breadcrumb field to each entry
+type breadcrumbCore struct {
+	zapcore.Core
+
+	crumbs string
+}
+
+func (c *breadcrumbCore) With(fields []zapcore.Field) zapcore.Core {
+	return &breadcrumbCore{Core: c.Core.With(fields), crumbs: c.crumbs}
+}
+
+func (c *breadcrumbCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	// Write through the wrapped Core's decision, e.g. from a sampler or the branches of a Tee
+	if inner := c.Core.Check(ent, nil); inner != nil {
+		return ce.AddCore(ent, &checkedCore{ce: inner, transform: c.crumb})
+	}
+
+	return ce
+}
+
+func (c *breadcrumbCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.crumb(fields))
+}
+
+// crumb returns a copy of fields with the breadcrumb field
+func (c *breadcrumbCore) crumb(fields []zapcore.Field) []zapcore.Field {
+	return append(fields[:len(fields):len(fields)], zap.String(BreadcrumbKey, c.crumbs))
+}
@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestBreadcrumbTee(t *testing.T) {
+	info, infoLogs := observer.New(zapcore.InfoLevel)
+	errs, errorLogs := observer.New(zapcore.ErrorLevel)
+
+	ctx := New(context.Background(), zapcore.NewTee(info, errs))
+	ctx = Breadcrumb(Breadcrumb(ctx, "auth"), "token")
+
+	Info(ctx, "crumb info")
+
+	if n := errorLogs.Len(); n != 0 {
+		t.Errorf("expected the error branch not to receive the info entry, got %d", n)
+	}
+
+	entries := infoLogs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected the info branch to receive 1 entry, got %d", len(entries))
+	}
+
+	if crumbs := entries[0].ContextMap()[BreadcrumbKey]; crumbs != "auth>token" {
+		t.Errorf("expected breadcrumb auth>token, got %v", crumbs)
+	}
+}
@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"context"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// CallerKey is the key of the field that InfoC and ErrorC add
+const CallerKey = "caller"
+
+// InfoC is a helper to log a single info-level message to a Context logger with a caller field naming the file and line
+// that called InfoC, without enabling zap.AddCaller for the Logger. The caller is only resolved if the entry is enabled
+func InfoC(ctx context.Context, msg string, fields ...zap.Field) {
+	logCaller(ctx, zapcore.InfoLevel, msg, fields)
+}
+
+// ErrorC is a helper to log a single error-level message to a Context logger with a caller field naming the file and
+// line that called ErrorC, without enabling zap.AddCaller for the Logger. The caller is only resolved if the entry is
+// enabled
+func ErrorC(ctx context.Context, msg string, fields ...zap.Field) {
+	logCaller(ctx, zapcore.ErrorLevel, msg, fields)
+}
+
+// logCaller writes an entry with the caller of the exported helper that called it
+func logCaller(ctx context.Context, lvl zapcore.Level, msg string, fields []zap.Field) {
+	ce := FromContext(ctx).Check(lvl, msg)
+	if ce == nil {
+		return
+	}
+
+	// Skip logCaller and the exported helper's frames
+	if caller := zapcore.NewEntryCaller(runtime.Caller(2)); caller.Defined {
+		fields = append(fields[:len(fields):len(fields)], zap.String(CallerKey, caller.TrimmedPath()))
+	}
+
+	ce.Write(fields...)
+}
@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Capture redirects a Context logger's output to a buffer in a child Context, e.g. to attach the entries that an
+// operation logged to an error report. Entries are encoded as JSON lines with zap's production encoder configuration,
+// at the level of the Logger's Core, with its tracked Fields. The returned function retrieves a copy of the entries
+// captured so far, and is safe for concurrent use. The Logger in the parent Context is unaffected. If the Context does
+// not have a Logger, entries are captured at info level
+func Capture(ctx context.Context) (context.Context, func() []byte) {
+	buf := &captureBuffer{}
+	encoder := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+
+	if HasLogger(ctx) {
+		fields := Fields(ctx)
+
+		ctx, _ = WithOptions(ctx, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewCore(encoder, buf, zapcore.LevelOf(core)).With(fields)
+		}))
+	} else {
+		ctx = New(ctx, zapcore.NewCore(encoder, buf, zapcore.InfoLevel))
+	}
+
+	return ctx, buf.bytes
+}
+
+// captureBuffer is a WriteSyncer that buffers entries in memory
+type captureBuffer struct {
+	sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *captureBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (*captureBuffer) Sync() error {
+	return nil
+}
+
+func (b *captureBuffer) bytes() []byte {
+	b.Lock()
+	defer b.Unlock()
+
+	return bytes.Clone(b.buf.Bytes())
+}
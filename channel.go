@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogEvent is an entry to be logged by a channel logger from NewChannelLogger
+type LogEvent struct {
+	Level   zapcore.Level
+	Message string
+	Fields  []zap.Field
+}
+
+// NewChannelLogger starts a goroutine that logs each LogEvent sent to the returned channel with the Context logger, so
+// that hot-path producers are decoupled from logging I/O. The channel buffers up to buffer events: when it is full,
+// sends block until the goroutine catches up, so producers that must never block should send in a select with a
+// default case and count dropped events. The returned function closes the channel and waits for the goroutine to log
+// the remaining buffered events. It may be called more than once, but events must not be sent after it has been
+// called. Canceling the Context does not stop the goroutine
+func NewChannelLogger(ctx context.Context, buffer int) (chan<- LogEvent, func()) {
+	logger := FromContext(ctx)
+	events := make(chan LogEvent, buffer)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for event := range events {
+			logger.Log(event.Level, event.Message, event.Fields...)
+		}
+	}()
+
+	var once sync.Once
+
+	return events, func() {
+		once.Do(func() {
+			close(events)
+		})
+
+		<-done
+	}
+}
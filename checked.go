@@ -0,0 +1,39 @@
+package logging
+
+import "go.uber.org/zap/zapcore"
+
+// checkedCore writes an entry through the CheckedEntry that a wrapped Core's Check returned, so that only the Cores
+// that accepted the entry, e.g. the matching branches of a Tee, receive it. Loggers set the entry's caller and stack
+// after Check, so the entry that is written replaces the retained one. If transform is not nil, it is applied to the
+// entry's fields before they are written
+type checkedCore struct {
+	ce        *zapcore.CheckedEntry
+	transform func([]zapcore.Field) []zapcore.Field
+}
+
+func (c *checkedCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (c *checkedCore) With([]zapcore.Field) zapcore.Core {
+	return c
+}
+
+func (c *checkedCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func (c *checkedCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if c.transform != nil {
+		fields = c.transform(fields)
+	}
+
+	c.ce.Entry = ent
+	c.ce.Write(fields...)
+
+	return nil
+}
+
+func (c *checkedCore) Sync() error {
+	return nil
+}
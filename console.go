@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ConsoleColorCore creates a Core that writes human-readable entries to w, using zap's development encoder
+// configuration with colored capital level names for local development. Colors are only enabled when w is a terminal,
+// so that output that is piped or redirected to a file does not contain escape codes
+func ConsoleColorCore(w io.Writer, lvl zapcore.LevelEnabler) zapcore.Core {
+	cfg := zap.NewDevelopmentEncoderConfig()
+	cfg.EncodeLevel = zapcore.CapitalLevelEncoder
+
+	if isTerminal(w) {
+		cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
+
+	return zapcore.NewCore(zapcore.NewConsoleEncoder(cfg), zapcore.AddSync(w), lvl)
+}
+
+// isTerminal reports whether a Writer is a character device, e.g. a terminal
+func isTerminal(w io.Writer) bool {
+	file, is := w.(*os.File)
+	if !is {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
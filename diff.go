@@ -0,0 +1,196 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Limits applied by Diff
+const (
+	// MaxDiffDepth is the number of nested structs, maps, and slices that Diff descends into. Values nested more deeply
+	// are compared with reflect.DeepEqual, and logged whole if they differ
+	MaxDiffDepth = 4
+
+	// MaxDiffChanges is the maximum number of changes that Diff logs. Entries with more changes have a truncated field
+	MaxDiffChanges = 64
+
+	// MaxDiffValueSize is the maximum number of bytes of each before and after value's JSON encoding that Diff logs.
+	// Larger values, e.g. long slices or deeply nested structs that are logged whole, are logged as a truncated string
+	// of their encoding, with a before_truncated or after_truncated field
+	MaxDiffValueSize = 256
+)
+
+// Diff is a helper to log a single info-level message to a Context logger with the differences between two values of
+// the same type, e.g. an entity before and after an update. Changes are logged as a changes object, with a key for the
+// path of each changed value (e.g. "Spec.Replicas" or "Labels.tier") and its before and after values. Exported struct
+// fields, map entries, and slice and array elements are compared, up to MaxDiffDepth. Slices of different lengths are
+// logged whole, and each value is truncated to MaxDiffValueSize bytes of JSON. Nothing is logged if the values are
+// equal
+func Diff(ctx context.Context, msg string, before, after any) {
+	var changes diffChanges
+
+	complete := changes.diff("", reflect.ValueOf(before), reflect.ValueOf(after), 0)
+	if len(changes) == 0 {
+		return
+	}
+
+	fields := []zap.Field{zap.Object("changes", changes)}
+	if !complete {
+		fields = append(fields, zap.Bool("truncated", true))
+	}
+
+	FromContext(ctx).Info(msg, fields...)
+}
+
+type diffChange struct {
+	path          string
+	before, after any
+}
+
+func (c *diffChange) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if err := addDiffValue(enc, "before", c.before); err != nil {
+		return err
+	}
+
+	return addDiffValue(enc, "after", c.after)
+}
+
+// addDiffValue adds a value's JSON encoding to an object, truncated to MaxDiffValueSize
+func addDiffValue(enc zapcore.ObjectEncoder, key string, val any) error {
+	encoded, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	if len(encoded) <= MaxDiffValueSize {
+		return enc.AddReflected(key, json.RawMessage(encoded))
+	}
+
+	enc.AddString(key, truncateUTF8(string(encoded), MaxDiffValueSize))
+	enc.AddBool(key+"_truncated", true)
+
+	return nil
+}
+
+type diffChanges []*diffChange
+
+func (changes diffChanges) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, change := range changes {
+		if err := enc.AddObject(change.path, change); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// add records a change, and returns false if the maximum number of changes has been reached
+func (changes *diffChanges) add(path string, before, after reflect.Value) bool {
+	if len(*changes) == MaxDiffChanges {
+		return false
+	}
+
+	if len(path) == 0 {
+		path = "value"
+	}
+
+	*changes = append(*changes, &diffChange{path: path, before: diffValue(before), after: diffValue(after)})
+	return true
+}
+
+// diff records changes between two values, and returns false if the maximum number of changes has been reached
+func (changes *diffChanges) diff(path string, a, b reflect.Value, depth int) bool {
+	if !a.IsValid() || !b.IsValid() || a.Type() != b.Type() {
+		if a.IsValid() == b.IsValid() && (!a.IsValid() || reflect.DeepEqual(a.Interface(), b.Interface())) {
+			return true
+		}
+
+		return changes.add(path, a, b)
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() && b.IsNil() {
+				return true
+			}
+
+			return changes.add(path, a, b)
+		}
+
+		return changes.diff(path, a.Elem(), b.Elem(), depth)
+
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		if depth >= MaxDiffDepth || (a.Kind() == reflect.Slice && a.Len() != b.Len()) {
+			break
+		}
+
+		switch a.Kind() {
+		case reflect.Struct:
+			for i := 0; i < a.NumField(); i++ {
+				if field := a.Type().Field(i); field.IsExported() {
+					if !changes.diff(diffPath(path, field.Name), a.Field(i), b.Field(i), depth+1) {
+						return false
+					}
+				}
+			}
+
+		case reflect.Map:
+			keys := a.MapKeys()
+			for _, key := range b.MapKeys() {
+				if !a.MapIndex(key).IsValid() {
+					keys = append(keys, key)
+				}
+			}
+
+			sort.Slice(keys, func(i, j int) bool {
+				return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+			})
+
+			for _, key := range keys {
+				if !changes.diff(diffPath(path, fmt.Sprint(key.Interface())), a.MapIndex(key), b.MapIndex(key), depth+1) {
+					return false
+				}
+			}
+
+		default:
+			for i := 0; i < a.Len(); i++ {
+				if !changes.diff(diffPath(path, strconv.Itoa(i)), a.Index(i), b.Index(i), depth+1) {
+					return false
+				}
+			}
+		}
+
+		return true
+	}
+
+	if reflect.DeepEqual(a.Interface(), b.Interface()) {
+		return true
+	}
+
+	return changes.add(path, a, b)
+}
+
+func diffPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// diffValue unwraps a reflected value, or returns nil for missing values
+func diffValue(val reflect.Value) any {
+	if !val.IsValid() {
+		return nil
+	}
+
+	return val.Interface()
+}
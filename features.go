@@ -0,0 +1,35 @@
+package logging
+
+import (
+	"context"
+	"maps"
+
+	"go.uber.org/zap"
+)
+
+// WithFlags records the feature flags that are enabled for a request in a child Context, so that verbose logging can be
+// gated on them with FlagEnabled, DebugFlag, and InfoFlag. The map is copied, so later changes to it are not visible
+func WithFlags(ctx context.Context, flags map[string]bool) context.Context {
+	return context.WithValue(ctx, flagsKey, maps.Clone(flags))
+}
+
+// FlagEnabled reports whether a feature flag was enabled for a Context with WithFlags. Expensive fields can be built
+// conditionally with FlagEnabled before calling a level helper
+func FlagEnabled(ctx context.Context, name string) bool {
+	flags, _ := ctx.Value(flagsKey).(map[string]bool)
+	return flags[name]
+}
+
+// DebugFlag is a helper to log a single debug-level message to a Context logger, only if a feature flag is enabled
+func DebugFlag(ctx context.Context, flag string, msg string, fields ...zap.Field) {
+	if FlagEnabled(ctx, flag) {
+		FromContext(ctx).Debug(msg, fields...)
+	}
+}
+
+// InfoFlag is a helper to log a single info-level message to a Context logger, only if a feature flag is enabled
+func InfoFlag(ctx context.Context, flag string, msg string, fields ...zap.Field) {
+	if FlagEnabled(ctx, flag) {
+		FromContext(ctx).Info(msg, fields...)
+	}
+}
@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Field keys used for durations by Elapsed, ElapsedMillis, and Duration
+const (
+	DurationKey       = "duration"
+	DurationMillisKey = "duration_ms"
+)
+
+// Elapsed constructs a duration field with the time elapsed since start
+func Elapsed(start time.Time) zap.Field {
+	return zap.Duration(DurationKey, time.Since(start))
+}
+
+// ElapsedMillis constructs an integer field with the number of milliseconds elapsed since start, for consumers that do
+// not depend upon the encoder's duration format
+func ElapsedMillis(start time.Time) zap.Field {
+	return zap.Int64(DurationMillisKey, time.Since(start).Milliseconds())
+}
+
+// Duration constructs a duration field for a duration that has already been measured
+func Duration(d time.Duration) zap.Field {
+	return zap.Duration(DurationKey, d)
+}
+
+// SafeString constructs a string field from untrusted input, replacing invalid UTF-8 sequences with the Unicode
+// replacement character so that the value can always be encoded
+func SafeString(key, val string) zap.Field {
+	if !utf8.ValidString(val) {
+		val = strings.ToValidUTF8(val, string(utf8.RuneError))
+	}
+
+	return zap.String(key, val)
+}
+
+// SafeBytes constructs a field from arbitrary bytes. Valid UTF-8 is logged as a string, and anything else is logged as
+// binary data, which encoders render in a safe form (e.g. base64 for JSON)
+func SafeBytes(key string, val []byte) zap.Field {
+	if utf8.Valid(val) {
+		return zap.ByteString(key, val)
+	}
+
+	return zap.Binary(key, val)
+}
+
+// truncateUTF8 cuts a string to at most n bytes, before any rune that would be split by the truncation
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
+
+// fieldString renders a field's value as a string
+func fieldString(field zap.Field) string {
+	switch field.Type {
+	case zapcore.StringType:
+		return field.String
+	case zapcore.StringerType:
+		return field.Interface.(fmt.Stringer).String()
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	field.AddTo(enc)
+
+	return fmt.Sprint(enc.Fields[field.Key])
+}
@@ -2,6 +2,18 @@ module github.com/jmanero/go-logging
 
 go 1.23.2
 
-require go.uber.org/zap v1.27.0
+require (
+	github.com/spf13/pflag v1.0.5
+	go.opentelemetry.io/otel v1.35.0
+	go.opentelemetry.io/otel/metric v1.35.0
+	go.uber.org/zap v1.27.0
+	golang.org/x/sync v0.10.0
+	google.golang.org/grpc v1.70.0
+)
 
-require go.uber.org/multierr v1.10.0 // indirect
+require (
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20241202173237-19429a94021a // indirect
+	google.golang.org/protobuf v1.35.2 // indirect
+)
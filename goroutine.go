@@ -0,0 +1,28 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Go runs fn in a new goroutine with a Context that is detached from ctx's cancellation, but retains its values,
+// including its Logger. A panic in fn is recovered and logged at error level with the context logger, and is not
+// re-raised, so the process continues running. fn should return when its own work is complete, as the detached
+// Context is never canceled
+func Go(ctx context.Context, fn func(context.Context)) {
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				FromContext(ctx).Error("recovered goroutine panic",
+					zap.String("panic", fmt.Sprint(r)),
+					zap.StackSkip("stack", 1))
+			}
+		}()
+
+		fn(ctx)
+	}()
+}
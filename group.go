@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// Group runs the concurrent sub-tasks of a request with an errgroup.Group, giving each task a Logger annotated with a
+// task field containing its index, counted from 0 in the order that tasks were started
+type Group struct {
+	group *errgroup.Group
+	ctx   context.Context
+	tasks atomic.Int64
+}
+
+// WithGroup creates a Group and a child Context, as errgroup.WithContext. The Context retains ctx's values, including
+// its Logger, and is canceled when a task first returns an error or when Wait returns. Unlike Go, WithGroup does not
+// detach the Context from ctx's cancellation: tasks are sub-tasks of the caller's work, which Wait returns before the
+// caller completes, so they are canceled along with ctx
+func WithGroup(ctx context.Context) (*Group, context.Context) {
+	group, ctx := errgroup.WithContext(ctx)
+	return &Group{group: group, ctx: ctx}, ctx
+}
+
+// Go runs fn in a new goroutine with a child of the Group's Context that has a task field added to its Logger
+func (g *Group) Go(fn func(ctx context.Context) error) {
+	task := g.tasks.Add(1) - 1
+
+	g.group.Go(func() error {
+		ctx, _ := With(g.ctx, zap.Int64("task", task))
+		return fn(ctx)
+	})
+}
+
+// SetLimit limits the number of tasks that may run concurrently, as errgroup.Group.SetLimit
+func (g *Group) SetLimit(n int) {
+	g.group.SetLimit(n)
+}
+
+// Wait blocks until all tasks have returned, and returns the first error returned by a task, if any
+func (g *Group) Wait() error {
+	return g.group.Wait()
+}
@@ -0,0 +1,48 @@
+// Package grpcstatus logs gRPC status errors with a Context logger. It is a separate package so that the logging
+// package does not depend upon gRPC
+package grpcstatus
+
+import (
+	"context"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LogStatus is a helper to log a single message with an error's gRPC status code to a Context logger, at the level
+// returned by Level, and to return the same error, e.g.
+//
+//	return nil, grpcstatus.LogStatus(ctx, status.Error(codes.NotFound, "no such user"), "lookup failed")
+//
+// Errors that do not carry a gRPC status are logged with the Unknown code at error level. A nil error is not logged
+func LogStatus(ctx context.Context, err error, msg string, fields ...zap.Field) error {
+	if err == nil {
+		return nil
+	}
+
+	code := status.Code(err)
+
+	logging.FromContext(ctx).Log(Level(code), msg, append(fields[:len(fields):len(fields)],
+		zap.Stringer("code", code),
+		zap.Error(err))...)
+
+	return err
+}
+
+// Level maps a gRPC status code to a log level. Codes that describe a problem with the client's request are logged at
+// info level, codes for transient conditions that a client may retry are logged at warn level, and codes that indicate
+// a server fault are logged at error level
+func Level(code codes.Code) zapcore.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.Unauthenticated, codes.FailedPrecondition, codes.OutOfRange:
+		return zapcore.InfoLevel
+	case codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Unavailable:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
@@ -1,11 +1,15 @@
 package logging
 
 import (
+	"context"
+	"sync/atomic"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-// Level extends zapcore.Level with pflag.Flag methods
+// Level extends zapcore.Level with pflag.Flag methods. *Level also implements the standard library's flag.Value
+// interface, for use with flag.Var
 type Level struct {
 	zap.AtomicLevel
 }
@@ -28,7 +32,117 @@ func (lvl *Level) Set(val string) error {
 	return nil
 }
 
+// String implements the flag.Value interface. The flag package calls String on zero values when printing usage, which
+// report zap's default info level
+func (lvl *Level) String() string {
+	if lvl == nil || lvl.AtomicLevel == (zap.AtomicLevel{}) {
+		return zapcore.InfoLevel.String()
+	}
+
+	return lvl.Level().String()
+}
+
 // Type implements the pflag.Flag interface for usage printing
 func (*Level) Type() string {
 	return "zap.Level"
 }
+
+// PushLevel derives a Logger from a Context that is gated at lvl instead of its Core's level, and injects it into a
+// child Context. Calling the returned function restores the derived Logger's prior level gating. e.g.
+//
+//	ctx, restore := logging.PushLevel(ctx, zapcore.DebugLevel)
+//	defer restore()
+//
+// Only the Logger in the returned Context and Loggers derived from it are affected. The Core's own Check method would
+// reject entries below its level, so those entries are checked as if they were at the Core's level, and are written at
+// their own level to the Cores that accept them, e.g. through a sampler or to the matching branches of a Tee
+func PushLevel(ctx context.Context, lvl zapcore.Level) (context.Context, func()) {
+	logger, is := ctx.Value(contextKey).(*zap.Logger)
+	if !is {
+		return ctx, func() {}
+	}
+
+	active := new(atomic.Bool)
+	active.Store(true)
+
+	logger = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &levelCore{Core: core, level: lvl, active: active}
+	}))
+
+	return context.WithValue(ctx, contextKey, logger), func() {
+		active.Store(false)
+	}
+}
+
+// levelCore overrides the level of a wrapped Core while active
+type levelCore struct {
+	zapcore.Core
+
+	level  zapcore.Level
+	active *atomic.Bool
+}
+
+func (c *levelCore) Level() zapcore.Level {
+	if c.active.Load() {
+		return c.level
+	}
+
+	return zapcore.LevelOf(c.Core)
+}
+
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	if c.active.Load() {
+		return c.level.Enabled(lvl)
+	}
+
+	return c.Core.Enabled(lvl)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level, active: c.active}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.active.Load() {
+		return c.Core.Check(ent, ce)
+	}
+
+	if !c.level.Enabled(ent.Level) {
+		return ce
+	}
+
+	if c.Core.Enabled(ent.Level) {
+		return c.Core.Check(ent, ce)
+	}
+
+	// The Core would reject the entry at its own level, so check it as if it were at the Core's level. Samplers and
+	// the branches of a Tee still decide whether, and where, it is written at its own level
+	raised := ent
+	raised.Level = zapcore.LevelOf(c.Core)
+	if raised.Level == zapcore.InvalidLevel {
+		return ce
+	}
+
+	inner := c.Core.Check(raised, nil)
+	if inner == nil {
+		return ce
+	}
+
+	inner.Entry.Level = ent.Level
+	return ce.AddCore(ent, &checkedCore{ce: inner})
+}
+
+// WithManagedLevel adds an existing Logger to a Context's values, as WithLogger, along with the Level that controls it,
+// so that handlers can retrieve the Level with ManagedLevel to adjust verbosity at runtime. The Logger's Core must have
+// been constructed with lvl.AtomicLevel as its LevelEnabler, e.g. by NewWithLevel, for changes to take effect. Any
+// other Core ignores the Level
+func WithManagedLevel(ctx context.Context, logger *zap.Logger, lvl *Level) context.Context {
+	return context.WithValue(WithLogger(ctx, logger), levelKey, lvl)
+}
+
+// ManagedLevel retrieves the Level that controls a Context's Logger, if one was added with WithManagedLevel. Changes to
+// the Level affect every Logger that shares its Core, not only the Logger in the Context
+func ManagedLevel(ctx context.Context) (*Level, bool) {
+	lvl, is := ctx.Value(levelKey).(*Level)
+	return lvl, is
+}
@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"context"
+	"flag"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLevelFlag(t *testing.T) {
+	var zero Level
+	lvl := NewLevel(zapcore.WarnLevel)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(lvl, "level", "log level")
+	fs.Var(&zero, "zero", "zero value log level")
+
+	var usage strings.Builder
+	fs.SetOutput(&usage)
+	fs.PrintDefaults()
+
+	if !strings.Contains(usage.String(), "(default warn)") {
+		t.Errorf("expected usage to include the initial level, got:\n%s", usage.String())
+	}
+
+	if s := zero.String(); s != "info" {
+		t.Errorf("expected a zero value Level to report info, got %q", s)
+	}
+
+	if err := fs.Parse([]string{"-level", "debug"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if lvl.Level() != zapcore.DebugLevel {
+		t.Errorf("expected the flag to set debug level, got %s", lvl.Level())
+	}
+
+	if err := fs.Parse([]string{"-level", "loud"}); err == nil {
+		t.Error("expected an invalid level to be rejected")
+	}
+}
+
+func TestPushLevelTee(t *testing.T) {
+	info, infoLogs := observer.New(zapcore.InfoLevel)
+	errs, errorLogs := observer.New(zapcore.ErrorLevel)
+
+	ctx, restore := PushLevel(New(context.Background(), zapcore.NewTee(info, errs)), zapcore.DebugLevel)
+	defer restore()
+
+	Debug(ctx, "pushed debug")
+
+	if n := errorLogs.Len(); n != 0 {
+		t.Errorf("expected the error branch not to receive the debug entry, got %d", n)
+	}
+
+	entries := infoLogs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected the info branch to receive 1 entry, got %d", len(entries))
+	}
+
+	if lvl := entries[0].Level; lvl != zapcore.DebugLevel {
+		t.Errorf("expected the entry to be written at debug, got %s", lvl)
+	}
+
+	restore()
+	Debug(ctx, "restored debug")
+
+	if n := infoLogs.Len(); n != 1 {
+		t.Errorf("expected no entries after restore, got %d", n-1)
+	}
+}
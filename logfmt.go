@@ -0,0 +1,425 @@
+package logging
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var logfmtPool = buffer.NewPool()
+
+// LogfmtCore creates a Core that writes logfmt lines to w, using zap's production encoder keys with RFC3339 timestamps
+func LogfmtCore(w io.Writer, lvl zapcore.LevelEnabler) zapcore.Core {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.EncodeTime = zapcore.RFC3339TimeEncoder
+
+	return zapcore.NewCore(NewLogfmtEncoder(cfg), zapcore.AddSync(w), lvl)
+}
+
+// NewLogfmtEncoder creates an Encoder that writes entries as lines of space-separated key=value pairs. Values that
+// contain spaces, quotes, equals signs, or non-printable characters are quoted. Fields of nested objects and namespaces
+// are flattened into dot-separated keys, and arrays are rendered as comma-separated lists in square brackets
+func NewLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{EncoderConfig: &cfg, buf: logfmtPool.Get()}
+}
+
+type logfmtEncoder struct {
+	*zapcore.EncoderConfig
+
+	buf    *buffer.Buffer
+	prefix string
+}
+
+func (enc *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := &logfmtEncoder{EncoderConfig: enc.EncoderConfig, buf: logfmtPool.Get(), prefix: enc.prefix}
+	clone.buf.Write(enc.buf.Bytes())
+
+	return clone
+}
+
+func (enc *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := &logfmtEncoder{EncoderConfig: enc.EncoderConfig, buf: logfmtPool.Get()}
+
+	if len(enc.TimeKey) > 0 {
+		final.AddTime(enc.TimeKey, ent.Time)
+	}
+
+	if len(enc.LevelKey) > 0 {
+		arr := &logfmtArray{config: enc.EncoderConfig, primitive: true}
+		if enc.EncodeLevel != nil {
+			enc.EncodeLevel(ent.Level, arr)
+		}
+
+		if len(arr.elems) > 0 {
+			final.addElement(enc.LevelKey, arr.elems[0])
+		} else {
+			final.AddString(enc.LevelKey, ent.Level.String())
+		}
+	}
+
+	if len(enc.NameKey) > 0 && len(ent.LoggerName) > 0 {
+		arr := &logfmtArray{config: enc.EncoderConfig, primitive: true}
+		if enc.EncodeName != nil {
+			enc.EncodeName(ent.LoggerName, arr)
+		}
+
+		if len(arr.elems) > 0 {
+			final.addElement(enc.NameKey, arr.elems[0])
+		} else {
+			final.AddString(enc.NameKey, ent.LoggerName)
+		}
+	}
+
+	if ent.Caller.Defined {
+		if len(enc.CallerKey) > 0 {
+			arr := &logfmtArray{config: enc.EncoderConfig, primitive: true}
+			if enc.EncodeCaller != nil {
+				enc.EncodeCaller(ent.Caller, arr)
+			}
+
+			if len(arr.elems) > 0 {
+				final.addElement(enc.CallerKey, arr.elems[0])
+			} else {
+				final.AddString(enc.CallerKey, ent.Caller.TrimmedPath())
+			}
+		}
+
+		if len(enc.FunctionKey) > 0 {
+			final.AddString(enc.FunctionKey, ent.Caller.Function)
+		}
+	}
+
+	if len(enc.MessageKey) > 0 {
+		final.AddString(enc.MessageKey, ent.Message)
+	}
+
+	// Append fields added with Logger.With
+	if enc.buf.Len() > 0 {
+		if final.buf.Len() > 0 {
+			final.buf.AppendByte(' ')
+		}
+
+		final.buf.Write(enc.buf.Bytes())
+	}
+
+	final.prefix = enc.prefix
+	for _, field := range fields {
+		field.AddTo(final)
+	}
+
+	final.prefix = ""
+	if len(enc.StacktraceKey) > 0 && len(ent.Stack) > 0 {
+		final.AddString(enc.StacktraceKey, ent.Stack)
+	}
+
+	if enc.SkipLineEnding {
+		return final.buf, nil
+	}
+
+	if len(enc.LineEnding) > 0 {
+		final.buf.AppendString(enc.LineEnding)
+	} else {
+		final.buf.AppendString(zapcore.DefaultLineEnding)
+	}
+
+	return final.buf, nil
+}
+
+// addKey writes a separator and a key, qualified by the current namespace, to the encoder's buffer
+func (enc *logfmtEncoder) addKey(key string) {
+	if enc.buf.Len() > 0 {
+		enc.buf.AppendByte(' ')
+	}
+
+	for _, r := range enc.prefix + key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			r = '_'
+		}
+
+		enc.buf.AppendString(string(r))
+	}
+
+	enc.buf.AppendByte('=')
+}
+
+// addElement writes a key and a value that has already been rendered to a string
+func (enc *logfmtEncoder) addElement(key, val string) {
+	enc.addKey(key)
+	appendLogfmtValue(enc.buf, val)
+}
+
+func (enc *logfmtEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	arr := &logfmtArray{config: enc.EncoderConfig}
+	err := marshaler.MarshalLogArray(arr)
+
+	enc.addElement(key, arr.String())
+	return err
+}
+
+func (enc *logfmtEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	prefix := enc.prefix
+	enc.prefix = prefix + key + "."
+
+	err := marshaler.MarshalLogObject(enc)
+	enc.prefix = prefix
+
+	return err
+}
+
+func (enc *logfmtEncoder) AddBinary(key string, val []byte) {
+	enc.AddString(key, base64.StdEncoding.EncodeToString(val))
+}
+
+func (enc *logfmtEncoder) AddByteString(key string, val []byte) {
+	enc.AddString(key, string(val))
+}
+
+func (enc *logfmtEncoder) AddBool(key string, val bool) {
+	enc.addKey(key)
+	enc.buf.AppendBool(val)
+}
+
+func (enc *logfmtEncoder) AddComplex128(key string, val complex128) {
+	enc.addKey(key)
+	enc.buf.AppendString(strconv.FormatComplex(val, 'g', -1, 128))
+}
+
+func (enc *logfmtEncoder) AddComplex64(key string, val complex64) {
+	enc.addKey(key)
+	enc.buf.AppendString(strconv.FormatComplex(complex128(val), 'g', -1, 64))
+}
+
+func (enc *logfmtEncoder) AddDuration(key string, val time.Duration) {
+	arr := &logfmtArray{config: enc.EncoderConfig, primitive: true}
+	arr.AppendDuration(val)
+
+	enc.addElement(key, arr.elems[0])
+}
+
+func (enc *logfmtEncoder) AddFloat64(key string, val float64) {
+	enc.addKey(key)
+	appendLogfmtFloat(enc.buf, val, 64)
+}
+
+func (enc *logfmtEncoder) AddFloat32(key string, val float32) {
+	enc.addKey(key)
+	appendLogfmtFloat(enc.buf, float64(val), 32)
+}
+
+func (enc *logfmtEncoder) AddInt(key string, val int)     { enc.AddInt64(key, int64(val)) }
+func (enc *logfmtEncoder) AddInt32(key string, val int32) { enc.AddInt64(key, int64(val)) }
+func (enc *logfmtEncoder) AddInt16(key string, val int16) { enc.AddInt64(key, int64(val)) }
+func (enc *logfmtEncoder) AddInt8(key string, val int8)   { enc.AddInt64(key, int64(val)) }
+
+func (enc *logfmtEncoder) AddInt64(key string, val int64) {
+	enc.addKey(key)
+	enc.buf.AppendInt(val)
+}
+
+func (enc *logfmtEncoder) AddString(key, val string) {
+	enc.addKey(key)
+	appendLogfmtValue(enc.buf, val)
+}
+
+func (enc *logfmtEncoder) AddTime(key string, val time.Time) {
+	arr := &logfmtArray{config: enc.EncoderConfig, primitive: true}
+	arr.AppendTime(val)
+
+	enc.addElement(key, arr.elems[0])
+}
+
+func (enc *logfmtEncoder) AddUint(key string, val uint)       { enc.AddUint64(key, uint64(val)) }
+func (enc *logfmtEncoder) AddUint32(key string, val uint32)   { enc.AddUint64(key, uint64(val)) }
+func (enc *logfmtEncoder) AddUint16(key string, val uint16)   { enc.AddUint64(key, uint64(val)) }
+func (enc *logfmtEncoder) AddUint8(key string, val uint8)     { enc.AddUint64(key, uint64(val)) }
+func (enc *logfmtEncoder) AddUintptr(key string, val uintptr) { enc.AddUint64(key, uint64(val)) }
+
+func (enc *logfmtEncoder) AddUint64(key string, val uint64) {
+	enc.addKey(key)
+	enc.buf.AppendUint(val)
+}
+
+func (enc *logfmtEncoder) AddReflected(key string, val interface{}) error {
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	enc.addElement(key, string(b))
+	return nil
+}
+
+func (enc *logfmtEncoder) OpenNamespace(key string) {
+	enc.prefix += key + "."
+}
+
+// logfmtArray renders array elements, and values from the EncoderConfig's primitive encoders, to strings. String
+// elements are quoted if they could be confused with the array's syntax, unless the array only captures a primitive
+// value that is quoted when it is written
+type logfmtArray struct {
+	config    *zapcore.EncoderConfig
+	elems     []string
+	primitive bool
+}
+
+func (arr *logfmtArray) String() string {
+	return "[" + strings.Join(arr.elems, ",") + "]"
+}
+
+func (arr *logfmtArray) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	nested := &logfmtArray{config: arr.config}
+	err := marshaler.MarshalLogArray(nested)
+
+	arr.elems = append(arr.elems, nested.String())
+	return err
+}
+
+func (arr *logfmtArray) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	nested := &logfmtEncoder{EncoderConfig: arr.config, buf: logfmtPool.Get()}
+	defer nested.buf.Free()
+
+	err := marshaler.MarshalLogObject(nested)
+
+	arr.elems = append(arr.elems, "{"+nested.buf.String()+"}")
+	return err
+}
+
+func (arr *logfmtArray) AppendReflected(val interface{}) error {
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	arr.appendString(string(b))
+	return nil
+}
+
+func (arr *logfmtArray) AppendBool(val bool) {
+	arr.elems = append(arr.elems, strconv.FormatBool(val))
+}
+
+func (arr *logfmtArray) AppendByteString(val []byte) {
+	arr.appendString(string(val))
+}
+
+func (arr *logfmtArray) AppendComplex128(val complex128) {
+	arr.elems = append(arr.elems, strconv.FormatComplex(val, 'g', -1, 128))
+}
+
+func (arr *logfmtArray) AppendComplex64(val complex64) {
+	arr.elems = append(arr.elems, strconv.FormatComplex(complex128(val), 'g', -1, 64))
+}
+
+func (arr *logfmtArray) AppendDuration(val time.Duration) {
+	size := len(arr.elems)
+	if arr.config.EncodeDuration != nil {
+		arr.config.EncodeDuration(val, arr)
+	}
+
+	if len(arr.elems) == size {
+		arr.elems = append(arr.elems, val.String())
+	}
+}
+
+func (arr *logfmtArray) AppendFloat64(val float64) {
+	arr.elems = append(arr.elems, strconv.FormatFloat(val, 'g', -1, 64))
+}
+
+func (arr *logfmtArray) AppendFloat32(val float32) {
+	arr.elems = append(arr.elems, strconv.FormatFloat(float64(val), 'g', -1, 32))
+}
+
+func (arr *logfmtArray) AppendInt(val int)     { arr.AppendInt64(int64(val)) }
+func (arr *logfmtArray) AppendInt32(val int32) { arr.AppendInt64(int64(val)) }
+func (arr *logfmtArray) AppendInt16(val int16) { arr.AppendInt64(int64(val)) }
+func (arr *logfmtArray) AppendInt8(val int8)   { arr.AppendInt64(int64(val)) }
+
+func (arr *logfmtArray) AppendInt64(val int64) {
+	arr.elems = append(arr.elems, strconv.FormatInt(val, 10))
+}
+
+func (arr *logfmtArray) AppendString(val string) {
+	arr.appendString(val)
+}
+
+func (arr *logfmtArray) AppendTime(val time.Time) {
+	size := len(arr.elems)
+	if arr.config.EncodeTime != nil {
+		arr.config.EncodeTime(val, arr)
+	}
+
+	if len(arr.elems) == size {
+		arr.elems = append(arr.elems, val.Format(time.RFC3339Nano))
+	}
+}
+
+func (arr *logfmtArray) AppendUint(val uint)       { arr.AppendUint64(uint64(val)) }
+func (arr *logfmtArray) AppendUint32(val uint32)   { arr.AppendUint64(uint64(val)) }
+func (arr *logfmtArray) AppendUint16(val uint16)   { arr.AppendUint64(uint64(val)) }
+func (arr *logfmtArray) AppendUint8(val uint8)     { arr.AppendUint64(uint64(val)) }
+func (arr *logfmtArray) AppendUintptr(val uintptr) { arr.AppendUint64(uint64(val)) }
+
+func (arr *logfmtArray) AppendUint64(val uint64) {
+	arr.elems = append(arr.elems, strconv.FormatUint(val, 10))
+}
+
+// appendString adds a string element, quoting it if it is empty or contains the array's delimiters
+func (arr *logfmtArray) appendString(val string) {
+	if !arr.primitive && logfmtQuoted(val, ",[]{}") {
+		val = strconv.Quote(val)
+	}
+
+	arr.elems = append(arr.elems, val)
+}
+
+// appendLogfmtValue writes a string value, quoting it if it is empty or contains anything other than printable,
+// non-space characters, quotes, and equals signs
+func appendLogfmtValue(buf *buffer.Buffer, val string) {
+	if logfmtQuoted(val, "") {
+		buf.AppendString(strconv.Quote(val))
+		return
+	}
+
+	buf.AppendString(val)
+}
+
+// logfmtQuoted reports whether a value must be quoted because it is empty or contains spaces, quotes, equals signs,
+// backslashes, non-printable characters, or any of the runes in special
+func logfmtQuoted(val, special string) bool {
+	if len(val) == 0 {
+		return true
+	}
+
+	for _, r := range val {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) ||
+			strings.ContainsRune(special, r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// appendLogfmtFloat writes a float value, quoting non-finite values
+func appendLogfmtFloat(buf *buffer.Buffer, val float64, size int) {
+	switch {
+	case math.IsNaN(val):
+		buf.AppendString(`"NaN"`)
+	case math.IsInf(val, 1):
+		buf.AppendString(`"+Inf"`)
+	case math.IsInf(val, -1):
+		buf.AppendString(`"-Inf"`)
+	default:
+		buf.AppendFloat(val, size)
+	}
+}
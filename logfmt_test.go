@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogfmtArrayQuoting(t *testing.T) {
+	enc := NewLogfmtEncoder(zapcore.EncoderConfig{MessageKey: "msg"})
+
+	buf, err := enc.EncodeEntry(zapcore.Entry{Message: "tags"}, []zapcore.Field{
+		zap.Strings("tags", []string{"a,b", "c"}),
+		zap.Strings("nested", []string{"[d]", "", "e f"}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer buf.Free()
+
+	line := buf.String()
+	for _, expected := range []string{
+		`tags="[\"a,b\",c]"`,
+		`nested="[\"[d]\",\"\",\"e f\"]"`,
+	} {
+		if !strings.Contains(line, expected) {
+			t.Errorf("expected %s in %q", expected, line)
+		}
+	}
+}
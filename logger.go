@@ -2,6 +2,8 @@ package logging
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -11,6 +13,14 @@ type contextKeyType uint8
 
 const (
 	contextKey contextKeyType = iota
+	fieldsKey
+	sampledKey
+	levelKey
+	scopeKey
+	timingsKey
+	auditKey
+	flagsKey
+	breadcrumbKey
 )
 
 var nop = zap.NewNop()
@@ -20,9 +30,22 @@ func New(ctx context.Context, core zapcore.Core, opts ...zap.Option) context.Con
 	return WithLogger(ctx, zap.New(core, opts...))
 }
 
-// WithLogger adds an existing Logger to a Context's values
+// NewWithLevel creates a new Logger with a Core that is gated by a Level, and injects it into a Context with
+// WithManagedLevel, so that its verbosity can be changed at runtime
+func NewWithLevel(ctx context.Context, encoder zapcore.Encoder, w zapcore.WriteSyncer, lvl *Level, opts ...zap.Option) context.Context {
+	return WithManagedLevel(ctx, zap.New(zapcore.NewCore(encoder, w, lvl.AtomicLevel), opts...), lvl)
+}
+
+// WithLogger adds an existing Logger to a Context's values. Fields tracked from parent Contexts are discarded
 func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
-	return context.WithValue(ctx, contextKey, logger)
+	return context.WithValue(context.WithValue(ctx, contextKey, logger), fieldsKey, (*trackedFields)(nil))
+}
+
+// Silence injects a no-op Logger into a child Context, so that logging helpers called with it and its descendants do
+// nothing, e.g. in a performance-critical subtree. Logging can be restored deeper in the subtree by injecting a real
+// Logger with WithLogger. HasLogger still reports true for a silenced Context, since the no-op Logger is a real Logger
+func Silence(ctx context.Context) context.Context {
+	return WithLogger(ctx, nop)
 }
 
 // FromContext attempts to retrieve a Logger from a Context's values
@@ -34,12 +57,33 @@ func FromContext(ctx context.Context) *zap.Logger {
 	return nop
 }
 
+// FromContextOrNil retrieves a Logger from a Context's values, or returns nil if the Context does not have one, so that
+// callers can choose their own fallback
+func FromContextOrNil(ctx context.Context) *zap.Logger {
+	logger, _ := ctx.Value(contextKey).(*zap.Logger)
+	return logger
+}
+
+// HasLogger reports whether a Logger has been added to a Context's values
+func HasLogger(ctx context.Context) bool {
+	_, is := ctx.Value(contextKey).(*zap.Logger)
+	return is
+}
+
+// Snapshot retrieves a Logger from a Context's values for reuse, e.g. before a loop that logs on every iteration. It is
+// equivalent to FromContext. Loggers are immutable and safe for concurrent use, so the returned Logger can be cached
+// for as long as the Context that it was retrieved from is in scope. Fields added to child Contexts with With or Named
+// are not reflected in a Logger that was retrieved from their parent
+func Snapshot(ctx context.Context) *zap.Logger {
+	return FromContext(ctx)
+}
+
 // With adds fields to a Logger and re-injects it into a child Context
 func With(ctx context.Context, fields ...zap.Field) (context.Context, *zap.Logger) {
 	if logger, is := ctx.Value(contextKey).(*zap.Logger); is {
 		logger = logger.With(fields...)
 
-		return context.WithValue(ctx, contextKey, logger), logger
+		return context.WithValue(track(ctx, fields), contextKey, logger), logger
 	}
 
 	return ctx, nop
@@ -52,6 +96,7 @@ func Named(ctx context.Context, name string, fields ...zap.Field) (context.Conte
 
 		if len(fields) > 0 {
 			logger = logger.With(fields...)
+			ctx = track(ctx, fields)
 		}
 
 		return context.WithValue(ctx, contextKey, logger), logger
@@ -60,12 +105,149 @@ func Named(ctx context.Context, name string, fields ...zap.Field) (context.Conte
 	return ctx, nop
 }
 
+// WithOptions applies options to a Logger and re-injects it into a child Context, retaining its tracked Fields
+func WithOptions(ctx context.Context, opts ...zap.Option) (context.Context, *zap.Logger) {
+	if logger, is := ctx.Value(contextKey).(*zap.Logger); is {
+		logger = logger.WithOptions(opts...)
+
+		return context.WithValue(ctx, contextKey, logger), logger
+	}
+
+	return ctx, nop
+}
+
+// Root sets the base name of a Context's Logger, so that subsequent Named calls build a predictable hierarchy, e.g.
+// "app.http.request". Root should be called where the Logger is created, before any Named calls. It is idempotent: the
+// Context is returned unchanged if its Logger is already named with the root, or with a descendant of the root.
+// Otherwise, the root is appended to any existing name, as with Named
+func Root(ctx context.Context, name string) context.Context {
+	if logger, is := ctx.Value(contextKey).(*zap.Logger); is {
+		if current := logger.Name(); current == name || strings.HasPrefix(current, name+".") {
+			return ctx
+		}
+
+		return context.WithValue(ctx, contextKey, logger.Named(name))
+	}
+
+	return ctx
+}
+
+// Fields retrieves the fields that have been added to a Context's Logger with With and Named, in the order that they
+// were added
+func Fields(ctx context.Context) []zap.Field {
+	tracked, _ := ctx.Value(fieldsKey).(*trackedFields)
+
+	n := 0
+	for node := tracked; node != nil; node = node.parent {
+		n += len(node.fields)
+	}
+
+	if n == 0 {
+		return nil
+	}
+
+	// Fill the list from its end, as the chain is walked from the most recently added fields
+	fields := make([]zap.Field, n)
+	for node := tracked; node != nil; node = node.parent {
+		n -= len(node.fields)
+		copy(fields[n:], node.fields)
+	}
+
+	return fields
+}
+
+// Lookup retrieves the most recently added field with a key from a Context's tracked Fields
+func Lookup(ctx context.Context, key string) (zap.Field, bool) {
+	tracked, _ := ctx.Value(fieldsKey).(*trackedFields)
+
+	for node := tracked; node != nil; node = node.parent {
+		for i := len(node.fields) - 1; i >= 0; i-- {
+			if node.fields[i].Key == key {
+				return node.fields[i], true
+			}
+		}
+	}
+
+	return zap.Field{}, false
+}
+
+// trackedFields links the fields added to a Context's Logger to the fields added to its parents' Loggers, so that
+// adding fields does not copy the fields that were added before them
+type trackedFields struct {
+	parent *trackedFields
+	fields []zap.Field
+}
+
+// track appends fields to a child Context's tracked Fields
+func track(ctx context.Context, fields []zap.Field) context.Context {
+	parent, _ := ctx.Value(fieldsKey).(*trackedFields)
+	return context.WithValue(ctx, fieldsKey, &trackedFields{parent: parent, fields: fields})
+}
+
+// Debug is a helper to log a single debug-level message to a Context logger
+func Debug(ctx context.Context, msg string, fields ...zap.Field) {
+	FromContext(ctx).Debug(msg, fields...)
+}
+
 // Info is a helper to log a single info-level message to a Context logger
 func Info(ctx context.Context, msg string, fields ...zap.Field) {
 	FromContext(ctx).Info(msg, fields...)
 }
 
+// Warn is a helper to log a single warn-level message to a Context logger
+func Warn(ctx context.Context, msg string, fields ...zap.Field) {
+	FromContext(ctx).Warn(msg, fields...)
+}
+
 // Error is a helper to log a single error-level message to a Context logger
 func Error(ctx context.Context, msg string, fields ...zap.Field) {
 	FromContext(ctx).Error(msg, fields...)
 }
+
+// Errors is a helper to log a single error-level message with a list of errors to a Context logger. Nothing is logged
+// if the list is empty
+func Errors(ctx context.Context, msg string, errs []error) {
+	if len(errs) == 0 {
+		return
+	}
+
+	FromContext(ctx).Error(msg, zap.Errors("errors", errs))
+}
+
+// LogErrorLeveled is a helper to log a single message with an error to a Context logger, at a level selected for the
+// error by levelFor. Nothing is logged if err is nil
+func LogErrorLeveled(ctx context.Context, err error, levelFor func(error) zapcore.Level, msg string, fields ...zap.Field) {
+	if err == nil {
+		return
+	}
+
+	FromContext(ctx).Log(levelFor(err), msg, append(fields, zap.Error(err))...)
+}
+
+// LogAt is a helper to log a single message to a Context logger at a level, with its timestamp set to t instead of
+// the current time, e.g. when replaying historical events. Sampling cores count the entry when it is checked, at the
+// current time
+func LogAt(ctx context.Context, t time.Time, lvl zapcore.Level, msg string, fields ...zap.Field) {
+	if ce := FromContext(ctx).Check(lvl, msg); ce != nil {
+		ce.Time = t
+		ce.Write(fields...)
+	}
+}
+
+// LogRetry is a helper to log a single warn-level "retrying" message to a Context logger with an attempt number, the
+// backoff duration before the next attempt, and the error that triggered the retry, if any. Attempts are counted as
+// with WithAttempt, so the first retry of a request is attempt 1
+func LogRetry(ctx context.Context, attempt int, backoff time.Duration, err error) {
+	fields := []zap.Field{zap.Int("attempt", attempt), zap.Duration("backoff", backoff)}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+
+	FromContext(ctx).Warn("retrying", fields...)
+}
+
+// Flush is a helper to flush any buffered entries from a Context logger's Core, e.g. before asserting upon the output
+// of a buffered or asynchronous WriteSyncer in tests, or before a process exits
+func Flush(ctx context.Context) error {
+	return FromContext(ctx).Sync()
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// benchmarkContext builds a Context with a Logger that discards its output, beneath a typical depth of other values
+func benchmarkContext() context.Context {
+	ctx := New(context.Background(), zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(io.Discard), zapcore.InfoLevel))
+
+	type key int
+	for i := 0; i < 8; i++ {
+		ctx = context.WithValue(ctx, key(i), i)
+	}
+
+	return ctx
+}
+
+func BenchmarkFromContext(b *testing.B) {
+	ctx := benchmarkContext()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		FromContext(ctx).Info("iteration", zap.Int("i", i))
+	}
+}
+
+func BenchmarkSnapshot(b *testing.B) {
+	logger := Snapshot(benchmarkContext())
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		logger.Info("iteration", zap.Int("i", i))
+	}
+}
+
+func TestFieldsSiblings(t *testing.T) {
+	parent, _ := With(New(context.Background(), zapcore.NewNopCore()), zap.String("request", "a"))
+	left, _ := With(parent, zap.String("side", "left"))
+	right, _ := Named(parent, "right", zap.String("side", "right"))
+
+	if fields := Fields(left); len(fields) != 2 || fields[0].Key != "request" || fields[1].String != "left" {
+		t.Errorf("expected the left Context to track its parent's fields followed by its own, got %v", fields)
+	}
+
+	if side, _ := Lookup(right, "side"); side.String != "right" {
+		t.Errorf("expected the right Context to find its own side field, got %q", side.String)
+	}
+
+	if _, has := Lookup(parent, "side"); has {
+		t.Error("expected the parent Context not to find its children's fields")
+	}
+}
+
+func BenchmarkWith(b *testing.B) {
+	ctx := benchmarkContext()
+	for i := 0; i < 16; i++ {
+		ctx, _ = With(ctx, zap.Int("depth", i))
+	}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		With(ctx, zap.Int("i", i))
+	}
+}
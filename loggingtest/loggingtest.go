@@ -0,0 +1,29 @@
+// Package loggingtest provides Context loggers for tests. It is a separate package so that importers of the logging
+// package do not link zaptest
+package loggingtest
+
+import (
+	"context"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap/zaptest"
+)
+
+// TestContext creates a Context with a Logger that writes entries synchronously to a test's log, at debug level unless
+// a zaptest.Level option is given. Entries are only printed for failed tests, or with go test -v.
+//
+// To assert upon the entries that code under test logs, inject an observer Core instead. Observer Cores record entries
+// synchronously, so they can be inspected as soon as the code under test returns, e.g.
+//
+//	core, logs := observer.New(zapcore.DebugLevel)
+//	ctx := logging.New(context.Background(), core)
+//
+//	Handle(ctx)
+//
+//	if logs.FilterMessage("request completed").Len() != 1 { ... }
+//
+// Tests of code that builds its own buffered or asynchronous Core should call logging.Flush before inspecting its
+// output
+func TestContext(t zaptest.TestingT, opts ...zaptest.LoggerOption) context.Context {
+	return logging.WithLogger(context.Background(), zaptest.NewLogger(t, opts...))
+}
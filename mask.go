@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Masker replaces a sensitive value with a masked form that is safe to log
+type Masker func(val string) string
+
+// MaskedValue is the replacement string used by MaskFixed
+const MaskedValue = "[MASKED]"
+
+// MaskFixed replaces a value with a fixed string, discarding it entirely
+func MaskFixed(string) string {
+	return MaskedValue
+}
+
+// MaskHash replaces a value with a prefix of its SHA-256 digest, so that log entries with the same value can be
+// correlated without logging it. Unsalted digests of values with few possibilities, e.g. phone numbers, can be
+// reversed by enumeration, so MaskFixed or a keyed Masker should be used for those
+func MaskHash(val string) string {
+	sum := sha256.Sum256([]byte(val))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// MaskEmail replaces the local part of an email address, preserving its first character and domain. Values that are
+// not email addresses are masked with MaskFixed
+func MaskEmail(val string) string {
+	at := strings.LastIndexByte(val, '@')
+	if at < 1 {
+		return MaskFixed(val)
+	}
+
+	return val[:1] + "***" + val[at:]
+}
+
+// DefaultMasker is used by Masked and WithMasked. It may be replaced during initialization, before any values are
+// masked
+var DefaultMasker Masker = MaskHash
+
+// Masked constructs a string field with a value masked by DefaultMasker
+func Masked(key, val string) zap.Field {
+	return zap.String(key, DefaultMasker(val))
+}
+
+// WithMasked adds a field with a value masked by DefaultMasker to a Logger and re-injects it into a child Context
+func WithMasked(ctx context.Context, key, val string) (context.Context, *zap.Logger) {
+	return With(ctx, Masked(key, val))
+}
+
+// MaskCore wraps a Core to mask the values of all fields with keys in rules, whether or not call sites used Masked.
+// Fields of any type are converted to strings before they are masked. Fields nested in objects and namespaces are not
+// inspected
+func MaskCore(core zapcore.Core, rules map[string]Masker) zapcore.Core {
+	return &maskCore{Core: core, rules: rules}
+}
+
+type maskCore struct {
+	zapcore.Core
+
+	rules map[string]Masker
+}
+
+func (c *maskCore) With(fields []zapcore.Field) zapcore.Core {
+	return &maskCore{Core: c.Core.With(c.mask(fields)), rules: c.rules}
+}
+
+func (c *maskCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	// Write through the wrapped Core's decision, so that masking does not disable sampling or Tee routing
+	if inner := c.Core.Check(ent, nil); inner != nil {
+		return ce.AddCore(ent, &checkedCore{ce: inner, transform: c.mask})
+	}
+
+	return ce
+}
+
+func (c *maskCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.mask(fields))
+}
+
+// mask returns a copy of fields with masked values, or the original slice if no fields have masked keys
+func (c *maskCore) mask(fields []zapcore.Field) []zapcore.Field {
+	var masked []zapcore.Field
+
+	for i, field := range fields {
+		mask, has := c.rules[field.Key]
+		if !has {
+			continue
+		}
+
+		if masked == nil {
+			masked = append([]zapcore.Field(nil), fields...)
+		}
+
+		masked[i] = zap.String(field.Key, mask(fieldString(field)))
+	}
+
+	if masked == nil {
+		return fields
+	}
+
+	return masked
+}
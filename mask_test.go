@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMaskCoreTee(t *testing.T) {
+	info, infoLogs := observer.New(zapcore.InfoLevel)
+	errs, errorLogs := observer.New(zapcore.ErrorLevel)
+
+	core := MaskCore(zapcore.NewTee(info, errs), map[string]Masker{"email": MaskFixed})
+	ctx := New(context.Background(), core, zap.AddCaller())
+
+	Info(ctx, "masked info", zap.String("email", "someone@example.com"))
+	Error(ctx, "masked error", zap.String("email", "someone@example.com"))
+
+	if n := infoLogs.Len(); n != 2 {
+		t.Fatalf("expected the info branch to receive 2 entries, got %d", n)
+	}
+
+	if n := errorLogs.FilterMessage("masked info").Len(); n != 0 {
+		t.Errorf("expected the error branch not to receive the info entry, got %d", n)
+	}
+
+	if n := errorLogs.FilterMessage("masked error").Len(); n != 1 {
+		t.Errorf("expected the error branch to receive the error entry, got %d", n)
+	}
+
+	for _, entry := range infoLogs.All() {
+		if email := entry.ContextMap()["email"]; email != MaskedValue {
+			t.Errorf("expected %q to be masked, got %v", entry.Message, email)
+		}
+
+		if !entry.Caller.Defined {
+			t.Errorf("expected %q to have a caller", entry.Message)
+		}
+	}
+}
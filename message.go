@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// MessageContext prepares a Context for processing a message from a queue or stream, mirroring tracing.Logger for HTTP
+// requests. A message name is appended to the Context's Logger with a string field for each non-empty metadata
+// attribute in key order, e.g. topic, partition, and offset, and the Context is given a request scope with WithScope.
+//
+// The returned function logs the message's completion with its duration, at info level when it is called with a nil
+// error and no error was recorded with SetError, or at error level with the error otherwise, e.g.
+//
+//	ctx, done := logging.MessageContext(ctx, map[string]string{"topic": msg.Topic, "offset": offset})
+//	done(handle(ctx, msg))
+func MessageContext(ctx context.Context, attrs map[string]string) (context.Context, func(err error)) {
+	keys := make([]string, 0, len(attrs))
+	for key, val := range attrs {
+		if len(val) > 0 {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	fields := make([]zap.Field, len(keys))
+	for i, key := range keys {
+		fields[i] = zap.String(key, attrs[key])
+	}
+
+	ctx, logger := Named(WithScope(ctx), "message", fields...)
+	start := time.Now()
+
+	return ctx, func(err error) {
+		if err == nil {
+			err = ScopeError(ctx)
+		}
+
+		if err != nil {
+			logger.Error("message failed", Elapsed(start), zap.Error(err))
+			return
+		}
+
+		logger.Info("message processed", Elapsed(start))
+	}
+}
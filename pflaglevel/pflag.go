@@ -0,0 +1,23 @@
+// Package pflaglevel registers logging.Level values as github.com/spf13/pflag flags. It is a separate package so that
+// the logging package does not depend upon pflag
+package pflaglevel
+
+import (
+	"github.com/jmanero/go-logging"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap/zapcore"
+)
+
+// RegisterFlag creates a Level with an initial value and registers it as a flag in a pflag.FlagSet. The initial level
+// is used as the flag's default value in usage output, e.g. "(default info)"
+func RegisterFlag(fs *pflag.FlagSet, name, usage string, initial zapcore.Level) *logging.Level {
+	return RegisterFlagP(fs, name, "", usage, initial)
+}
+
+// RegisterFlagP is like RegisterFlag, but accepts a shorthand letter that can be used after a single dash
+func RegisterFlagP(fs *pflag.FlagSet, name, shorthand, usage string, initial zapcore.Level) *logging.Level {
+	lvl := logging.NewLevel(initial)
+	fs.VarP(lvl, name, shorthand, usage)
+
+	return lvl
+}
@@ -0,0 +1,33 @@
+package pflaglevel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRegisterFlagPUsage(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	lvl := RegisterFlagP(fs, "log-level", "l", "log level", zapcore.InfoLevel)
+
+	var usage strings.Builder
+	fs.SetOutput(&usage)
+
+	if err := fs.Parse([]string{"--help"}); err != pflag.ErrHelp {
+		t.Fatalf("expected --help to return pflag.ErrHelp, got %v", err)
+	}
+
+	if out := usage.String(); !strings.Contains(out, "-l, --log-level zap.Level") || !strings.Contains(out, "(default info)") {
+		t.Errorf("expected usage to include the flag and its default level, got:\n%s", out)
+	}
+
+	if err := fs.Parse([]string{"-l", "error"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if lvl.Level() != zapcore.ErrorLevel {
+		t.Errorf("expected the shorthand to set error level, got %s", lvl.Level())
+	}
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Phase appends a phase name to a Logger and re-injects it into a child Context. The returned function logs a
+// debug-level entry with the phase's duration when called, e.g. with defer, and reports it to the Context's
+// TimingObserver
+func Phase(ctx context.Context, name string) (context.Context, *zap.Logger, func()) {
+	ctx, logger := Named(ctx, name)
+	start := time.Now()
+
+	return ctx, logger, func() {
+		observeTiming(ctx, logger, start)
+		logger.Debug("phase completed", Elapsed(start))
+	}
+}
+
+// StartOp appends an operation name and fields to a Logger, re-injects it into a child Context, and logs a debug-level
+// start entry. The returned function logs the operation's completion with its duration, at info level when it is
+// called with a nil error, or at error level with the error otherwise. The duration is reported to the Context's
+// TimingObserver
+func StartOp(ctx context.Context, name string, fields ...zap.Field) (context.Context, func(err error)) {
+	ctx, logger := Named(ctx, name, fields...)
+	start := time.Now()
+
+	logger.Debug("operation started")
+
+	return ctx, func(err error) {
+		observeTiming(ctx, logger, start)
+
+		if err != nil {
+			logger.Error("operation failed", Elapsed(start), zap.Error(err))
+			return
+		}
+
+		logger.Info("operation completed", Elapsed(start))
+	}
+}
+
+// Heartbeat logs an info-level entry with the Context logger at each interval until the returned function is called or
+// the Context is canceled, so that long-running operations do not appear to have hung. If fields is not nil, it is
+// called for each entry to report the operation's progress, from the heartbeat's goroutine. The returned function
+// waits for the goroutine to exit, and may be called more than once. Heartbeat panics if interval is not positive, as
+// time.NewTicker does, from the caller's goroutine rather than the heartbeat's
+func Heartbeat(ctx context.Context, interval time.Duration, fields func() []zap.Field) func() {
+	if interval <= 0 {
+		panic("logging: non-positive interval for Heartbeat")
+	}
+
+	logger := FromContext(ctx)
+	start := time.Now()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			entry := []zap.Field{Elapsed(start)}
+			if fields != nil {
+				entry = append(entry, fields()...)
+			}
+
+			logger.Info("heartbeat", entry...)
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			close(stop)
+		})
+
+		<-done
+	}
+}
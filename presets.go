@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewProduction creates a Logger equivalent to zap.NewProduction and injects it into a Context: JSON entries at info
+// level and above are written to standard error, sampled after the first 100 entries with the same level and message
+// in each second, with caller annotations and stacktraces for error level and above. Additional options are applied
+// after the preset's options
+func NewProduction(ctx context.Context, opts ...zap.Option) context.Context {
+	stderr := zapcore.Lock(os.Stderr)
+
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), stderr, zapcore.InfoLevel)
+	core = zapcore.NewSamplerWithOptions(core, time.Second, 100, 100)
+
+	return New(ctx, core, append([]zap.Option{
+		zap.ErrorOutput(stderr),
+		zap.AddCaller(),
+		zap.AddStacktrace(zapcore.ErrorLevel),
+	}, opts...)...)
+}
+
+// NewDevelopment creates a Logger equivalent to zap.NewDevelopment and injects it into a Context: human-readable
+// entries at debug level and above are written to standard error, with caller annotations and stacktraces for warn
+// level and above, and DPanic level entries panic. Additional options are applied after the preset's options
+func NewDevelopment(ctx context.Context, opts ...zap.Option) context.Context {
+	stderr := zapcore.Lock(os.Stderr)
+	core := zapcore.NewCore(zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig()), stderr, zapcore.DebugLevel)
+
+	return New(ctx, core, append([]zap.Option{
+		zap.ErrorOutput(stderr),
+		zap.Development(),
+		zap.AddCaller(),
+		zap.AddStacktrace(zapcore.WarnLevel),
+	}, opts...)...)
+}
@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// ContextHeader is the suggested header name for propagating values encoded by EncodeContext
+const ContextHeader = "X-Log-Context"
+
+// Limits applied by EncodeContext and DecodeContext
+const (
+	// MaxContextSize is the maximum length of an encoded context value. Fields that would exceed it are omitted
+	MaxContextSize = 4096
+
+	// MaxContextFields is the maximum number of fields that DecodeContext attaches
+	MaxContextFields = 32
+)
+
+// EncodeContext serializes the values of the named fields from a Context's tracked Fields into a compact header value,
+// for propagation to downstream services that attach them with DecodeContext. Fields that are not present are skipped.
+//
+// The wire format is a comma-separated list of key=value pairs, e.g. "tenant=acme,session=a1b2", with keys and values
+// escaped with url.QueryEscape. All values are rendered as strings. Pairs that would make the value longer than
+// MaxContextSize are omitted
+func EncodeContext(ctx context.Context, keys ...string) string {
+	var buf strings.Builder
+
+	for _, key := range keys {
+		field, has := Lookup(ctx, key)
+		if !has {
+			continue
+		}
+
+		pair := url.QueryEscape(key) + "=" + url.QueryEscape(fieldString(field))
+		if buf.Len() > 0 {
+			pair = "," + pair
+		}
+
+		if buf.Len()+len(pair) > MaxContextSize {
+			continue
+		}
+
+		buf.WriteString(pair)
+	}
+
+	return buf.String()
+}
+
+// DecodeContext parses a header value produced by EncodeContext, adds the pairs with the named keys to a Logger as
+// string fields, and re-injects it into a child Context. Header values are supplied by clients, so pairs with other
+// keys are dropped, to prevent spoofed fields like id or user_id. Values longer than MaxContextSize are ignored,
+// malformed pairs are skipped, and at most MaxContextFields pairs are attached
+func DecodeContext(ctx context.Context, header string, keys ...string) (context.Context, *zap.Logger) {
+	if len(header) == 0 || len(header) > MaxContextSize {
+		return ctx, FromContext(ctx)
+	}
+
+	var fields []zap.Field
+
+	for _, pair := range strings.Split(header, ",") {
+		if len(fields) == MaxContextFields {
+			break
+		}
+
+		key, val, found := strings.Cut(pair, "=")
+		if !found {
+			continue
+		}
+
+		key, err := url.QueryUnescape(key)
+		if err != nil || !slices.Contains(keys, key) {
+			continue
+		}
+
+		val, err = url.QueryUnescape(val)
+		if err != nil {
+			continue
+		}
+
+		fields = append(fields, SafeString(key, val))
+	}
+
+	return With(ctx, fields...)
+}
@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// MaxQueryLength is the number of bytes of a query that LogQuery logs. Longer queries are truncated
+const MaxQueryLength = 1024
+
+// LogQuery is a helper to log a single database query's execution to a Context logger, with query, duration, and rows
+// fields, at debug level if err is nil, or at error level with the error otherwise. Queries longer than MaxQueryLength
+// are truncated at a UTF-8 boundary and flagged with a query_truncated field. A negative rows count, e.g. for a
+// statement whose driver does not report one, is omitted. Queries should be logged with placeholders rather than
+// interpolated arguments, so that values are not leaked into logs
+func LogQuery(ctx context.Context, query string, duration time.Duration, rows int, err error) {
+	lvl := zapcore.DebugLevel
+	if err != nil {
+		lvl = zapcore.ErrorLevel
+	}
+
+	ce := FromContext(ctx).Check(lvl, "query executed")
+	if ce == nil {
+		return
+	}
+
+	truncated := len(query) > MaxQueryLength
+	if truncated {
+		query = truncateUTF8(query, MaxQueryLength)
+	}
+
+	fields := []zap.Field{SafeString("query", query), Duration(duration)}
+	if truncated {
+		fields = append(fields, zap.Bool("query_truncated", true))
+	}
+
+	if rows >= 0 {
+		fields = append(fields, zap.Int("rows", rows))
+	}
+
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+
+	ce.Write(fields...)
+}
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RingCore creates a Core that encodes entries as NDJSON, with zap's production encoder configuration, into a Ring that
+// retains the most recent capacity entries, e.g. for an in-app recent logs endpoint. Combine it with a process's
+// primary Core with zapcore.NewTee. Memory is bounded by capacity times the size of the largest retained entries:
+// each entry's encoding is copied into its own slot, and released when it is overwritten
+func RingCore(capacity int, lvl zapcore.LevelEnabler) (zapcore.Core, *Ring) {
+	ring := &Ring{entries: make([][]byte, capacity)}
+	return zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), ring, lvl), ring
+}
+
+// Ring is a bounded, concurrency-safe buffer of recently written entries. Ring implements http.Handler: requests
+// respond with the retained entries as NDJSON, oldest first
+type Ring struct {
+	sync.Mutex
+
+	entries [][]byte
+	next    int
+	full    bool
+}
+
+// Write retains a copy of an encoded entry, replacing the oldest entry if the Ring is full
+func (r *Ring) Write(b []byte) (int, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if len(r.entries) == 0 {
+		return len(b), nil
+	}
+
+	r.entries[r.next] = bytes.Clone(b)
+	r.next = (r.next + 1) % len(r.entries)
+	r.full = r.full || r.next == 0
+
+	return len(b), nil
+}
+
+// Sync implements zapcore.WriteSyncer
+func (*Ring) Sync() error {
+	return nil
+}
+
+// Entries retrieves the retained entries, oldest first. The returned slices must not be modified
+func (r *Ring) Entries() [][]byte {
+	r.Lock()
+	defer r.Unlock()
+
+	if !r.full {
+		return append([][]byte(nil), r.entries[:r.next]...)
+	}
+
+	return append(append([][]byte(nil), r.entries[r.next:]...), r.entries[:r.next]...)
+}
+
+// ServeHTTP writes the retained entries as NDJSON
+func (r *Ring) ServeHTTP(wr http.ResponseWriter, _ *http.Request) {
+	wr.Header().Set("Content-Type", "application/x-ndjson")
+
+	for _, entry := range r.Entries() {
+		wr.Write(entry)
+	}
+}
@@ -0,0 +1,278 @@
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateOption configures a RotatingFile
+type RotateOption func(*rotateOptions)
+
+type rotateOptions struct {
+	maxSize    int64
+	interval   time.Duration
+	maxBackups int
+	maxAge     time.Duration
+	compress   bool
+}
+
+// RotateWithMaxSize rotates the file before a write would grow it past size bytes. A single write that is larger than
+// size is written to a new file without being split
+func RotateWithMaxSize(size int64) RotateOption {
+	return func(opts *rotateOptions) {
+		opts.maxSize = size
+	}
+}
+
+// RotateWithInterval rotates the file before the first write after it has been open for an interval
+func RotateWithInterval(interval time.Duration) RotateOption {
+	return func(opts *rotateOptions) {
+		opts.interval = interval
+	}
+}
+
+// RotateWithMaxBackups retains at most n rotated backup files, removing the oldest after each rotation
+func RotateWithMaxBackups(n int) RotateOption {
+	return func(opts *rotateOptions) {
+		opts.maxBackups = n
+	}
+}
+
+// RotateWithMaxAge removes rotated backup files that were last modified more than age ago, after each rotation
+func RotateWithMaxAge(age time.Duration) RotateOption {
+	return func(opts *rotateOptions) {
+		opts.maxAge = age
+	}
+}
+
+// RotateWithCompression compresses rotated backup files with gzip, adding a .gz extension
+func RotateWithCompression() RotateOption {
+	return func(opts *rotateOptions) {
+		opts.compress = true
+	}
+}
+
+// RotatingFile is a zapcore.WriteSyncer that appends to a file and rotates it by size or age, for use when constructing
+// a Core, e.g.
+//
+//	file, err := logging.NewRotatingFile("/var/log/app.log", logging.RotateWithMaxSize(100<<20), logging.RotateWithMaxBackups(7))
+//	core := zapcore.NewCore(encoder, file, zapcore.InfoLevel)
+//
+// Rotation renames the file with a timestamp suffix, e.g. app.log.2006-01-02T15-04-05.000, and reopens the original
+// path. By default every backup is retained without compression. Backups are compressed and removed by retention
+// options in a background goroutine, so that writes are not blocked. A RotatingFile is safe for concurrent writes
+type RotatingFile struct {
+	path    string
+	options rotateOptions
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+
+	// Serialize compression and retention of backups
+	mill    sync.Mutex
+	milling sync.WaitGroup
+}
+
+// NewRotatingFile opens or creates a file for appending, and rotates it with options
+func NewRotatingFile(path string, opts ...RotateOption) (*RotatingFile, error) {
+	f := &RotatingFile{path: path}
+	for _, opt := range opts {
+		opt(&f.options)
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Write appends to the file, rotating it first if the write would exceed its maximum size or its interval has elapsed
+func (f *RotatingFile) Write(b []byte) (n int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.size > 0 && ((f.options.maxSize > 0 && f.size+int64(len(b)) > f.options.maxSize) ||
+		(f.options.interval > 0 && time.Since(f.opened) >= f.options.interval)) {
+		if err = f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err = f.file.Write(b)
+	f.size += int64(n)
+
+	return
+}
+
+// Sync flushes the file to its storage
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+
+	return f.file.Sync()
+}
+
+// Rotate rotates the file immediately, e.g. when a process receives SIGHUP
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+
+	return f.rotate()
+}
+
+// Close closes the file, and waits for pending compression and retention of backups to complete
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+
+	f.mu.Unlock()
+	f.milling.Wait()
+
+	return err
+}
+
+// open opens the file at its path for appending
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file, f.size, f.opened = file, info.Size(), time.Now()
+	return nil
+}
+
+// rotate renames the file to a backup and reopens its path. The original path is reopened if the rename fails, so
+// that writes can continue
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+
+	f.file = nil
+	backup := f.path + "." + time.Now().Format("2006-01-02T15-04-05.000")
+
+	renamed := os.Rename(f.path, backup)
+	if err := f.open(); err != nil {
+		return errors.Join(renamed, err)
+	}
+
+	if renamed != nil {
+		return renamed
+	}
+
+	if f.options.compress || f.options.maxBackups > 0 || f.options.maxAge > 0 {
+		f.milling.Add(1)
+		go f.millBackups(backup)
+	}
+
+	return nil
+}
+
+// millBackups compresses a new backup and removes backups that exceed retention options. Errors are discarded: the
+// logger has nowhere to report them
+func (f *RotatingFile) millBackups(backup string) {
+	defer f.milling.Done()
+
+	f.mill.Lock()
+	defer f.mill.Unlock()
+
+	if f.options.compress {
+		compressFile(backup)
+	}
+
+	dir := filepath.Dir(f.path)
+	prefix := filepath.Base(f.path) + "."
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var backups []os.DirEntry
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.HasPrefix(entry.Name(), prefix) {
+			backups = append(backups, entry)
+		}
+	}
+
+	// Timestamp suffixes sort chronologically, newest first
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name() > backups[j].Name()
+	})
+
+	for i, entry := range backups {
+		expired := f.options.maxBackups > 0 && i >= f.options.maxBackups
+
+		if !expired && f.options.maxAge > 0 {
+			if info, err := entry.Info(); err == nil && time.Since(info.ModTime()) > f.options.maxAge {
+				expired = true
+			}
+		}
+
+		if expired {
+			os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
+
+// compressFile replaces a file with a gzip-compressed copy
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+
+	_, err = io.Copy(gz, src)
+	err = errors.Join(err, gz.Close(), dst.Close())
+
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	src.Close()
+	return os.Remove(path)
+}
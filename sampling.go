@@ -0,0 +1,198 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithSamplingDecision records a head-based sampling decision for a request in a child Context. The Logger in the
+// Context of a sampled request is gated at debug level with PushLevel, so that Debug and the other level helpers emit
+// verbose entries for it regardless of its Core's level
+func WithSamplingDecision(ctx context.Context, sampled bool) context.Context {
+	ctx = context.WithValue(ctx, sampledKey, sampled)
+
+	if sampled {
+		ctx, _ = PushLevel(ctx, zapcore.DebugLevel)
+	}
+
+	return ctx
+}
+
+// Sampled retrieves the sampling decision recorded in a Context with WithSamplingDecision
+func Sampled(ctx context.Context) bool {
+	sampled, _ := ctx.Value(sampledKey).(bool)
+	return sampled
+}
+
+// SampleID makes a deterministic sampling decision for a fraction of identifiers. Seeding decisions from an identifier
+// that is propagated between services, e.g. a trace or request ID, samples the same requests in every service
+func SampleID(id string, rate float64) bool {
+	hash := fnv.New64a()
+	hash.Write([]byte(id))
+
+	return float64(hash.Sum64()) < rate*math.MaxUint64
+}
+
+// SamplingConfig configures a Sampling register. In each Tick, the First entries with a given level and message are
+// logged, followed by every Thereafter-th entry. A zero Tick disables sampling, and a zero Thereafter drops every entry
+// after the First in each Tick
+type SamplingConfig struct {
+	Tick       time.Duration
+	First      int
+	Thereafter int
+}
+
+// samplingPayload is the JSON representation of a SamplingConfig
+type samplingPayload struct {
+	Tick       string `json:"tick"`
+	First      int    `json:"first"`
+	Thereafter int    `json:"thereafter"`
+}
+
+// samplingCounters is the number of counters for each level. Messages whose hashes collide share a counter
+const samplingCounters = 1024
+
+// Sampling is a register for a sampling configuration that can be changed at runtime, similar to Level. Cores wrapped
+// with its Core method read the current configuration for each sampling decision, so changes take effect immediately
+// without rebuilding the Logger. All of its methods are safe for concurrent use.
+//
+// Sampling implements http.Handler: GET requests respond with the current configuration as JSON, e.g.
+// {"tick":"1s","first":100,"thereafter":100}, and PUT requests replace it with a JSON configuration in the same format
+type Sampling struct {
+	config   atomic.Pointer[SamplingConfig]
+	counters *[zapcore.FatalLevel - zapcore.DebugLevel + 1][samplingCounters]samplingCounter
+}
+
+// NewSampling instantiates a new Sampling register for an initial configuration
+func NewSampling(config SamplingConfig) *Sampling {
+	s := &Sampling{counters: new([zapcore.FatalLevel - zapcore.DebugLevel + 1][samplingCounters]samplingCounter)}
+	s.config.Store(&config)
+
+	return s
+}
+
+// Config retrieves the current sampling configuration
+func (s *Sampling) Config() SamplingConfig {
+	return *s.config.Load()
+}
+
+// SetConfig replaces the sampling configuration. Entries that have already been counted in the current tick count
+// towards the new configuration's limits
+func (s *Sampling) SetConfig(config SamplingConfig) error {
+	if config.Tick < 0 || config.First < 0 || config.Thereafter < 0 {
+		return errors.New("sampling tick, first, and thereafter must not be negative")
+	}
+
+	s.config.Store(&config)
+	return nil
+}
+
+// Core wraps a Core with a sampler that reads the register's configuration for each entry, e.g. with zap.WrapCore
+func (s *Sampling) Core(core zapcore.Core) zapcore.Core {
+	return &samplingCore{Core: core, sampling: s}
+}
+
+// ServeHTTP reports or replaces the sampling configuration
+func (s *Sampling) ServeHTTP(wr http.ResponseWriter, req *http.Request) {
+	enc := json.NewEncoder(wr)
+	wr.Header().Set("Content-Type", "application/json")
+
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var payload samplingPayload
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			wr.WriteHeader(http.StatusBadRequest)
+			enc.Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		tick, err := time.ParseDuration(payload.Tick)
+		if err == nil {
+			err = s.SetConfig(SamplingConfig{Tick: tick, First: payload.First, Thereafter: payload.Thereafter})
+		}
+
+		if err != nil {
+			wr.WriteHeader(http.StatusBadRequest)
+			enc.Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+	default:
+		wr.Header().Set("Allow", "GET, PUT")
+		wr.WriteHeader(http.StatusMethodNotAllowed)
+		enc.Encode(map[string]string{"error": "only GET and PUT are supported"})
+		return
+	}
+
+	config := s.Config()
+	enc.Encode(samplingPayload{Tick: config.Tick.String(), First: config.First, Thereafter: config.Thereafter})
+}
+
+// sample decides whether an entry should be logged
+func (s *Sampling) sample(ent zapcore.Entry) bool {
+	config := s.config.Load()
+	if config.Tick <= 0 || ent.Level < zapcore.DebugLevel || ent.Level > zapcore.FatalLevel {
+		return true
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(ent.Message))
+
+	n := s.counters[ent.Level-zapcore.DebugLevel][hash.Sum32()%samplingCounters].inc(ent.Time, config.Tick)
+	if n <= uint64(config.First) {
+		return true
+	}
+
+	return config.Thereafter > 0 && (n-uint64(config.First))%uint64(config.Thereafter) == 0
+}
+
+// samplingCounter counts entries in a tick
+type samplingCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// inc increments the counter, resetting it first if its tick has elapsed
+func (c *samplingCounter) inc(t time.Time, tick time.Duration) uint64 {
+	now := t.UnixNano()
+
+	resetAt := c.resetAt.Load()
+	if resetAt > now {
+		return c.count.Add(1)
+	}
+
+	c.count.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, now+tick.Nanoseconds()) {
+		return c.count.Add(1)
+	}
+
+	return 1
+}
+
+// samplingCore samples entries with a Sampling register's current configuration
+type samplingCore struct {
+	zapcore.Core
+
+	sampling *Sampling
+}
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplingCore{Core: c.Core.With(fields), sampling: c.sampling}
+}
+
+func (c *samplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) || !c.sampling.sample(ent) {
+		return ce
+	}
+
+	return c.Core.Check(ent, ce)
+}
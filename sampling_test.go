@@ -0,0 +1,29 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSamplingDecisionTee(t *testing.T) {
+	info, infoLogs := observer.New(zapcore.InfoLevel)
+	errs, errorLogs := observer.New(zapcore.ErrorLevel)
+
+	ctx := WithSamplingDecision(New(context.Background(), zapcore.NewTee(info, errs)), true)
+	if !Sampled(ctx) {
+		t.Fatal("expected the Context to be sampled")
+	}
+
+	Debug(ctx, "sampled debug")
+
+	if n := errorLogs.Len(); n != 0 {
+		t.Errorf("expected the error branch not to receive the debug entry, got %d", n)
+	}
+
+	if n := infoLogs.FilterMessage("sampled debug").Len(); n != 1 {
+		t.Errorf("expected the info branch to receive 1 debug entry, got %d", n)
+	}
+}
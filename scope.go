@@ -0,0 +1,203 @@
+package logging
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// scope holds mutable logging state for a single request
+type scope struct {
+	sync.Mutex
+
+	once  map[string]struct{}
+	err   error
+	first error
+	tags  map[string]string
+	wide  []zap.Field
+}
+
+// WithScope attaches a request-scoped state holder, used by helpers like LogOnce, to a child Context. The holder is
+// shared by every Context derived from the returned Context, and is discarded with them when the request completes. If
+// a Context already has a holder, it is returned unchanged, so that nested middleware functions share one holder.
+// tracing.Logger calls WithScope for each request
+func WithScope(ctx context.Context) context.Context {
+	if scopeFrom(ctx) != nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, scopeKey, &scope{})
+}
+
+func scopeFrom(ctx context.Context) *scope {
+	s, _ := ctx.Value(scopeKey).(*scope)
+	return s
+}
+
+// LogOnce is a helper to log a single message to a Context logger at most once for each key in a request scope. If the
+// Context does not have a request scope from WithScope, the message is logged on every call
+func LogOnce(ctx context.Context, key string, lvl zapcore.Level, msg string, fields ...zap.Field) {
+	if s := scopeFrom(ctx); s != nil {
+		s.Lock()
+
+		_, seen := s.once[key]
+		if !seen {
+			if s.once == nil {
+				s.once = make(map[string]struct{})
+			}
+
+			s.once[key] = struct{}{}
+		}
+
+		s.Unlock()
+
+		if seen {
+			return
+		}
+	}
+
+	FromContext(ctx).Log(lvl, msg, fields...)
+}
+
+// SetError records an error in a Context's request scope, e.g. for an error that a handler recovered from but still
+// wants to be reported in tracing.Logger's completion entry, which logs it at error level. Each call replaces the
+// previously recorded error, and a nil error clears it. The error is discarded with the request scope when the request
+// completes. SetError does nothing if the Context does not have a request scope from WithScope
+func SetError(ctx context.Context, err error) {
+	if s := scopeFrom(ctx); s != nil {
+		s.Lock()
+		s.err = err
+		s.Unlock()
+	}
+}
+
+// ScopeError retrieves the error recorded in a Context's request scope by SetError
+func ScopeError(ctx context.Context) error {
+	s := scopeFrom(ctx)
+	if s == nil {
+		return nil
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	return s.err
+}
+
+// FirstError retrieves the first error that was logged in a Context's request scope by LogFirstError
+func FirstError(ctx context.Context) error {
+	s := scopeFrom(ctx)
+	if s == nil {
+		return nil
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	return s.first
+}
+
+// LogFirstError is a helper to log a single error-level message with an error to a Context logger, only if no error has
+// been logged by LogFirstError in the request scope yet, so that layered handlers can each report a failure without
+// repeating its root cause. Nil errors are ignored. If the Context does not have a request scope from WithScope, every
+// error is logged
+func LogFirstError(ctx context.Context, err error, msg string, fields ...zap.Field) {
+	if err == nil {
+		return
+	}
+
+	if s := scopeFrom(ctx); s != nil {
+		s.Lock()
+
+		logged := s.first != nil
+		if !logged {
+			s.first = err
+		}
+
+		s.Unlock()
+
+		if logged {
+			return
+		}
+	}
+
+	FromContext(ctx).Error(msg, append(fields[:len(fields):len(fields)], zap.Error(err))...)
+}
+
+// Tag adds a string field to a Context's Logger, re-injects it into a child Context, and records it in the request
+// scope's tags, so that one call sets both a log field and a metric dimension, e.g. a tenant. Metrics middleware, like
+// otelmetrics.OTelMetrics, reads the request's Tags when it completes. Tags become metric attributes, so their values
+// must have a bounded cardinality: never tag with user or request identifiers. A later Tag with the same key replaces
+// its recorded value. If the Context does not have a request scope from WithScope, the tag is only added to the Logger
+func Tag(ctx context.Context, key, value string) context.Context {
+	if s := scopeFrom(ctx); s != nil {
+		s.Lock()
+
+		if s.tags == nil {
+			s.tags = make(map[string]string)
+		}
+
+		s.tags[key] = value
+		s.Unlock()
+	}
+
+	ctx, _ = With(ctx, zap.String(key, value))
+	return ctx
+}
+
+// Tags retrieves a copy of the tags recorded in a Context's request scope by Tag
+func Tags(ctx context.Context) map[string]string {
+	s := scopeFrom(ctx)
+	if s == nil {
+		return nil
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	return maps.Clone(s.tags)
+}
+
+// AddField accumulates fields in a Context's request scope, to be merged into a single wide entry when the request
+// completes, e.g. tracing.Logger's completion entry, which includes them after its own fields. Fields are kept in the
+// order that their keys were first added: adding a field with a key that has already been accumulated replaces its
+// value in place. AddField is safe for concurrent use within a request. Fields are discarded if the Context does not
+// have a request scope from WithScope
+func AddField(ctx context.Context, fields ...zap.Field) {
+	s := scopeFrom(ctx)
+	if s == nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+next:
+	for _, field := range fields {
+		for i := range s.wide {
+			if s.wide[i].Key == field.Key {
+				s.wide[i] = field
+				continue next
+			}
+		}
+
+		s.wide = append(s.wide, field)
+	}
+}
+
+// AccumulatedFields retrieves a copy of the fields accumulated in a Context's request scope by AddField
+func AccumulatedFields(ctx context.Context) []zap.Field {
+	s := scopeFrom(ctx)
+	if s == nil {
+		return nil
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	return slices.Clone(s.wide)
+}
@@ -0,0 +1,88 @@
+//go:build !windows && !plan9
+
+package logging
+
+import (
+	"log/syslog"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SyslogCore creates a Core that writes JSON entries, encoded with zap's production encoder configuration, to a syslog
+// daemon with the user facility, at a syslog severity mapped from each entry's level. network and addr are passed to
+// syslog.Dial: empty values connect to the local daemon. syslog.Writer reconnects and retries once when a write fails.
+// SyslogCore is not available on Windows or Plan 9
+func SyslogCore(network, addr, tag string, lvl zapcore.LevelEnabler) (zapcore.Core, error) {
+	writer, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &syslogCore{
+		LevelEnabler: lvl,
+		enc:          zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		writer:       writer,
+	}, nil
+}
+
+// syslogCore writes encoded entries to a syslog.Writer
+type syslogCore struct {
+	zapcore.LevelEnabler
+
+	enc    zapcore.Encoder
+	writer *syslog.Writer
+}
+
+func (c *syslogCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.LevelEnabler)
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	return &syslogCore{LevelEnabler: c.LevelEnabler, enc: enc, writer: c.writer}
+}
+
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+
+	msg := strings.TrimSuffix(buf.String(), "\n")
+	buf.Free()
+
+	switch ent.Level {
+	case zapcore.DebugLevel:
+		return c.writer.Debug(msg)
+	case zapcore.InfoLevel:
+		return c.writer.Info(msg)
+	case zapcore.WarnLevel:
+		return c.writer.Warning(msg)
+	case zapcore.ErrorLevel:
+		return c.writer.Err(msg)
+	case zapcore.DPanicLevel:
+		return c.writer.Crit(msg)
+	case zapcore.PanicLevel:
+		return c.writer.Alert(msg)
+	default:
+		return c.writer.Emerg(msg)
+	}
+}
+
+func (c *syslogCore) Sync() error {
+	return nil
+}
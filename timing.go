@@ -0,0 +1,28 @@
+package logging
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TimingObserver receives the durations of operations measured by StartOp and Phase, keyed by the full name of the
+// operation's Logger (e.g. "request.db.query"). Observers are called from the goroutine that completes each operation,
+// and must be safe for concurrent use. Names should be bounded, e.g. not derived from request data, if an observer
+// feeds them to a metrics backend
+type TimingObserver func(name string, d time.Duration)
+
+// WithTimings adds a TimingObserver to a Context's values, so that operations measured with StartOp and Phase in child
+// Contexts feed a latency histogram per named Logger. Registration is optional: operations are only logged without an
+// observer. See the otelmetrics package for an observer that records an OpenTelemetry histogram
+func WithTimings(ctx context.Context, observer TimingObserver) context.Context {
+	return context.WithValue(ctx, timingsKey, observer)
+}
+
+// observeTiming reports the duration of an operation to the Context's TimingObserver, if it has one
+func observeTiming(ctx context.Context, logger *zap.Logger, start time.Time) {
+	if observer, is := ctx.Value(timingsKey).(TimingObserver); is && observer != nil {
+		observer(logger.Name(), time.Since(start))
+	}
+}
@@ -0,0 +1,123 @@
+package tracing
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// AccessLogFormat selects the line format used to render access log entries
+type AccessLogFormat uint8
+
+// Supported AccessLogFormat values
+const (
+	// CommonLogFormat renders NCSA Common Log Format lines
+	CommonLogFormat AccessLogFormat = iota
+
+	// CombinedLogFormat renders Common Log Format lines with additional Referer and User-Agent values
+	CombinedLogFormat
+)
+
+// CommonLogTime is the timestamp layout used in Common Log Format lines
+const CommonLogTime = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLog is a middleware function that only writes a line in the given AccessLogFormat to w for each request,
+// instead of a structured log entry. Use LoggerWithAccessLog to write both
+func AccessLog(w io.Writer, format AccessLogFormat) func(http.Handler) http.Handler {
+	access := &accessLog{w: w, format: format}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+			writer := &ResponseWriterProxy{ResponseWriter: wr, Status: http.StatusOK}
+			start := time.Now()
+
+			next.ServeHTTP(writer, req)
+			access.write(req, writer, start)
+		})
+	}
+}
+
+type accessLog struct {
+	sync.Mutex
+
+	w      io.Writer
+	format AccessLogFormat
+}
+
+func (l *accessLog) write(req *http.Request, writer *ResponseWriterProxy, start time.Time) {
+	line := AppendAccessLog(nil, l.format, req, writer.Status, writer.Size, start)
+
+	l.Lock()
+	defer l.Unlock()
+
+	l.w.Write(line)
+}
+
+// AppendAccessLog renders a newline-terminated access log line for a completed request to buf
+func AppendAccessLog(buf []byte, format AccessLogFormat, req *http.Request, status, size int, start time.Time) []byte {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	user, _, _ := req.BasicAuth()
+
+	buf = appendToken(buf, host)
+	buf = append(buf, " - "...)
+	buf = appendToken(buf, user)
+	buf = append(buf, " ["...)
+	buf = start.AppendFormat(buf, CommonLogTime)
+	buf = append(buf, "] "...)
+	buf = strconv.AppendQuote(buf, req.Method+" "+req.RequestURI+" "+req.Proto)
+	buf = append(buf, ' ')
+	buf = strconv.AppendInt(buf, int64(status), 10)
+	buf = append(buf, ' ')
+
+	if size > 0 {
+		buf = strconv.AppendInt(buf, int64(size), 10)
+	} else {
+		buf = append(buf, '-')
+	}
+
+	if format == CombinedLogFormat {
+		buf = append(buf, ' ')
+		buf = appendQuoted(buf, req.Referer())
+		buf = append(buf, ' ')
+		buf = appendQuoted(buf, req.UserAgent())
+	}
+
+	return append(buf, '\n')
+}
+
+// appendToken writes an unquoted value, or a hyphen if it is empty. Spaces, control characters, non-ASCII bytes,
+// quotes, backslashes, and brackets are escaped as \xHH, as Apache does, so that client-supplied values like Basic auth
+// usernames can not forge fields or lines
+func appendToken(buf []byte, val string) []byte {
+	if len(val) == 0 {
+		return append(buf, '-')
+	}
+
+	const hex = "0123456789abcdef"
+
+	for i := 0; i < len(val); i++ {
+		if c := val[i]; c <= ' ' || c >= 0x7f || c == '"' || c == '\\' || c == '[' || c == ']' {
+			buf = append(buf, '\\', 'x', hex[c>>4], hex[c&0xf])
+		} else {
+			buf = append(buf, c)
+		}
+	}
+
+	return buf
+}
+
+// appendQuoted writes an escaped, quoted value, or a quoted hyphen if it is empty
+func appendQuoted(buf []byte, val string) []byte {
+	if len(val) == 0 {
+		return append(buf, `"-"`...)
+	}
+
+	return strconv.AppendQuote(buf, val)
+}
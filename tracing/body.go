@@ -0,0 +1,165 @@
+package tracing
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap"
+)
+
+// MaxBody is a middleware function that limits request bodies to a maximum number of bytes with http.MaxBytesReader.
+// Requests that declare a larger Content-Length are rejected immediately. Otherwise, a warning is logged when a handler
+// reads past the limit, and a 413 response is sent if the handler has not already written one.
+//
+// MaxBody should be wrapped inside of Logger, so that its warnings carry request fields and the 413 status is captured
+// in the completion entry. Logger's req_size field then counts the bytes consumed from the client, which is at most one
+// byte more than the limit
+func MaxBody(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+			if req.ContentLength > limit {
+				logging.FromContext(req.Context()).Warn("request body too large",
+					zap.Int64("limit", limit),
+					zap.Int64("content_length", req.ContentLength))
+
+				http.Error(wr, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			writer := &headerWriter{ResponseWriter: wr}
+			reader := &maxBytesReader{ReadCloser: http.MaxBytesReader(wr, req.Body, limit)}
+
+			req.Body = reader
+
+			next.ServeHTTP(writer, req)
+
+			if reader.exceeded {
+				logging.FromContext(req.Context()).Warn("request body too large", zap.Int64("limit", limit))
+
+				if !writer.wrote {
+					http.Error(writer, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				}
+			}
+		})
+	}
+}
+
+// maxBytesReader records whether an http.MaxBytesReader has reached its limit
+type maxBytesReader struct {
+	io.ReadCloser
+
+	exceeded bool
+}
+
+func (r *maxBytesReader) Read(b []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(b)
+
+	var mbe *http.MaxBytesError
+	if errors.As(err, &mbe) {
+		r.exceeded = true
+	}
+
+	return
+}
+
+// headerWriter records whether a handler has started writing a response
+type headerWriter struct {
+	http.ResponseWriter
+
+	wrote bool
+}
+
+func (w *headerWriter) WriteHeader(status int) {
+	w.wrote = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *headerWriter) Write(b []byte) (int, error) {
+	w.wrote = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController
+func (w *headerWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Decompress builds a middleware function that transparently decompresses request bodies with a gzip
+// Content-Encoding, and logs the compressed and decompressed sizes of the body after the handler has returned. The
+// Content-Encoding and Content-Length headers are removed from the request that is passed to the handler. Bodies
+// without a valid gzip header are rejected with a 400 response.
+//
+// Decompressed bodies are limited to limit bytes, to protect handlers from small compressed bodies that expand without
+// bound. A warning is logged when a handler reads past the limit, and a 413 response is sent if the handler has not
+// already written one. MaxBody, wrapped outside of Decompress, only limits the compressed size. A limit of zero or less
+// disables the decompressed limit.
+//
+// When Decompress is wrapped inside of Logger, Logger's req_size field counts compressed bytes
+func Decompress(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+			encoding := req.Header.Get("Content-Encoding")
+			if !strings.EqualFold(encoding, "gzip") {
+				next.ServeHTTP(wr, req)
+				return
+			}
+
+			logger := logging.FromContext(req.Context())
+			compressed := &ReadCloserProxy{ReadCloser: req.Body}
+
+			reader, err := gzip.NewReader(compressed)
+			if err != nil {
+				logger.Warn("invalid compressed request body", zap.String("content_encoding", encoding), zap.Error(err))
+				http.Error(wr, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+
+				return
+			}
+
+			var body io.ReadCloser = &gzipBody{Reader: reader, body: compressed}
+			writer := &headerWriter{ResponseWriter: wr}
+
+			limited := &maxBytesReader{ReadCloser: body}
+			if limit > 0 {
+				limited.ReadCloser = http.MaxBytesReader(wr, body, limit)
+			}
+
+			decompressed := &ReadCloserProxy{ReadCloser: limited}
+
+			req.Body = decompressed
+			req.ContentLength = -1
+			req.Header.Del("Content-Encoding")
+			req.Header.Del("Content-Length")
+
+			next.ServeHTTP(writer, req)
+
+			logger.Info("request body decompressed",
+				zap.String("content_encoding", encoding),
+				zap.Int("compressed_size", compressed.Size),
+				zap.Int("decompressed_size", decompressed.Size))
+
+			if limited.exceeded {
+				logger.Warn("decompressed request body too large", zap.Int64("limit", limit))
+
+				if !writer.wrote {
+					http.Error(writer, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				}
+			}
+		})
+	}
+}
+
+// gzipBody closes both a gzip.Reader and the request body that it reads from
+type gzipBody struct {
+	*gzip.Reader
+
+	body io.Closer
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
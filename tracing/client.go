@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap"
+)
+
+// Transport wraps an http.RoundTripper to log outbound requests with the request's context logger, and to propagate
+// the request identifier from RequestID if the request does not already have one. Identifiers are propagated in the
+// header that the Identifier middleware was configured with, X-Request-ID by default. The completion entry is logged
+// when the response body is closed, so that its size can be accumulated, or immediately for a 101 Switching Protocols
+// response, whose body is left untouched. A nil base uses http.DefaultTransport
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	logger := logging.FromContext(req.Context()).Named("client").With(
+		logging.SafeString("method", req.Method),
+		logging.SafeString("url", req.URL.Redacted()))
+
+	if rid, is := req.Context().Value(requestIDKey).(requestID); is && len(req.Header.Get(rid.header)) == 0 {
+		// RoundTrippers must not modify the caller's request
+		req = req.Clone(req.Context())
+		req.Header.Set(rid.header, rid.id)
+	}
+
+	start := time.Now()
+
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		logger.Error("client request failed", zap.Error(err), logging.Elapsed(start))
+		return res, err
+	}
+
+	if res.StatusCode == http.StatusSwitchingProtocols {
+		// The body of an upgraded connection is an io.ReadWriteCloser, and wrapping it would hide its Write method
+		logger.Info("client request completed", zap.Int("status", res.StatusCode), logging.Elapsed(start))
+		return res, nil
+	}
+
+	res.Body = &clientBody{
+		ReadCloserProxy: ReadCloserProxy{ReadCloser: res.Body},
+		logger:          logger,
+		status:          res.StatusCode,
+		start:           start,
+	}
+
+	return res, nil
+}
+
+// clientBody logs a client request's completion when its response body is closed
+type clientBody struct {
+	ReadCloserProxy
+
+	logger *zap.Logger
+	status int
+	start  time.Time
+	once   sync.Once
+}
+
+func (b *clientBody) Close() error {
+	err := b.ReadCloserProxy.Close()
+
+	b.once.Do(func() {
+		b.logger.Info("client request completed",
+			zap.Int("status", b.status),
+			zap.Int("res_size", b.Size),
+			logging.Elapsed(b.start),
+		)
+	})
+
+	return err
+}
@@ -0,0 +1,35 @@
+package tracing
+
+import "net/http"
+
+// uncompressedSize holds the uncompressed size of a response for Logger
+type uncompressedSize struct {
+	size int
+	set  bool
+}
+
+// Uncompressed is a middleware function that counts the bytes that a handler writes before they are compressed. It must
+// be wrapped inside of a response compression middleware function, which is in turn wrapped inside of Logger, e.g.
+//
+//	handler = tracing.Uncompressed(handler)
+//	handler = Compress(handler)
+//	handler = tracing.Logger(handler)
+//
+// When the response has a Content-Encoding header, Logger's completion entry then includes the uncompressed size as a
+// res_size_uncompressed field, and a compression_ratio field with the ratio of the uncompressed size to res_size.
+// Neither field is logged for responses that were not compressed
+func Uncompressed(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+		holder, is := req.Context().Value(uncompressedKey).(*uncompressedSize)
+		if !is {
+			next.ServeHTTP(wr, req)
+			return
+		}
+
+		writer := &ResponseWriterProxy{ResponseWriter: wr, Status: http.StatusOK}
+		next.ServeHTTP(writer, req)
+
+		holder.size = writer.Size
+		holder.set = true
+	})
+}
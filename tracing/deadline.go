@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap"
+)
+
+// DeadlineWarning is a middleware function that logs a warning if a request is still being handled after a fraction of
+// the time remaining before its Context's deadline has elapsed, e.g. 0.8 for 80%. Requests without a deadline are not
+// watched. Deadlines are set by outer middleware, e.g. http.TimeoutHandler, not by http.Server's timeouts. The
+// watcher is stopped when the handler returns
+func DeadlineWarning(threshold float64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+			deadline, has := req.Context().Deadline()
+			if !has {
+				next.ServeHTTP(wr, req)
+				return
+			}
+
+			logger := logging.FromContext(req.Context())
+			start := time.Now()
+			budget := deadline.Sub(start)
+
+			timer := time.AfterFunc(time.Duration(float64(budget)*threshold), func() {
+				logger.Warn("request approaching deadline", logging.Elapsed(start), zap.Duration("budget", budget))
+			})
+
+			defer timer.Stop()
+
+			next.ServeHTTP(wr, req)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap/zapcore"
+)
+
+// DebugHeaderPrefix is prepended to field keys to name the response headers set by DebugHeaders
+const DebugHeaderPrefix = "X-Debug-"
+
+// DebugHeaders is a development middleware function that returns the values of the selected context logger fields, as
+// tracked by logging.Fields, to the client in X-Debug-<key> response headers. Response headers can not be modified
+// once a handler has started writing its response, so the headers are set before calling the handler, and only
+// include fields added by outer middleware functions, e.g. the id field from Identifier.
+//
+// Field values can leak internal information to clients. DebugHeaders returns handlers unchanged when it is called
+// without any keys, so that it can be disabled from configuration in production
+func DebugHeaders(keys ...string) func(http.Handler) http.Handler {
+	if len(keys) == 0 {
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+			for _, key := range keys {
+				if field, is := logging.Lookup(req.Context(), key); is {
+					enc := zapcore.NewMapObjectEncoder()
+					field.AddTo(enc)
+
+					wr.Header().Set(DebugHeaderPrefix+key, fmt.Sprint(enc.Fields[key]))
+				}
+			}
+
+			next.ServeHTTP(wr, req)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package tracing
+
+import "reflect"
+
+// FieldNames configures the keys of the fields that Identifier, Span, and Logger emit, so that they can conform to an
+// existing log schema. The same FieldNames value should be given to each. Empty names use DefaultFieldNames
+type FieldNames struct {
+	// Identifier and Span fields
+	ID           string
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+
+	// Logger request fields. Local is the local address of the connection that received the request, when the server
+	// provides it, and ALPN is the protocol negotiated by TLS ALPN, e.g. h2, when one was negotiated
+	Host   string
+	Scheme string
+	Proto  string
+	Method string
+	Path   string
+	Local  string
+	ALPN   string
+
+	// Logger completion fields. ResSize counts the bytes written to Logger's ResponseWriterProxy, so Logger should wrap
+	// any response compression middleware to count the compressed bytes that are sent to the client. QueueTime is the
+	// time between a connection being accepted and its first request being handled, if the server's ConnContext
+	// function is set to ConnContext. Later requests on a reused keep-alive connection do not include it
+	ReqSize        string
+	Status         string
+	ResSize        string
+	Duration       string
+	DurationMillis string
+	QueueTime      string
+	Error          string
+
+	// Logger optional completion fields. ResEncoding is the response's Content-Encoding header, which identifies
+	// ResSize as a compressed size, and ResSizeUncompressed and CompressionRatio require the Uncompressed middleware.
+	// ExpectContinue marks requests with an Expect: 100-continue header, and ContinueSent reports whether the handler
+	// read the body before writing its response, which is when http.Server sends the 100 Continue response. WriteError
+	// is the error from writing the response body, e.g. when the client aborted the connection, and raises the
+	// completion entry to warn level or higher
+	ResEncoding         string
+	ResSizeUncompressed string
+	CompressionRatio    string
+	ReqHeaders          string
+	ReqHeaderSize       string
+	ResHeaders          string
+	ResHeaderSize       string
+	Accept              string
+	ContentType         string
+	Cookies             string
+	Headers             string
+	PathValues          string
+	ExpectContinue      string
+	ContinueSent        string
+	MethodNotAllowed    string
+	LargeResponse       string
+	CPUTime             string
+	CPURatio            string
+	WriteError          string
+}
+
+// DefaultFieldNames are the keys of the fields that Identifier, Span, and Logger emit by default
+var DefaultFieldNames = FieldNames{
+	ID:           "id",
+	TraceID:      "trace_id",
+	SpanID:       "span_id",
+	ParentSpanID: "parent_span_id",
+
+	Host:   "host",
+	Scheme: "scheme",
+	Proto:  "proto",
+	Method: "method",
+	Path:   "path",
+	Local:  "local",
+	ALPN:   "alpn",
+
+	ReqSize:        "req_size",
+	Status:         "status",
+	ResSize:        "res_size",
+	Duration:       "duration",
+	DurationMillis: "duration_ms",
+	QueueTime:      "queue_time",
+	Error:          "error",
+
+	ResEncoding:         "res_encoding",
+	ResSizeUncompressed: "res_size_uncompressed",
+	CompressionRatio:    "compression_ratio",
+	ReqHeaders:          "req_headers",
+	ReqHeaderSize:       "req_header_size",
+	ResHeaders:          "res_headers",
+	ResHeaderSize:       "res_header_size",
+	Accept:              "accept",
+	ContentType:         "content_type",
+	Cookies:             "cookies",
+	Headers:             "headers",
+	PathValues:          "path_values",
+	ExpectContinue:      "expect_continue",
+	ContinueSent:        "continue_sent",
+	MethodNotAllowed:    "method_not_allowed",
+	LargeResponse:       "large_response",
+	CPUTime:             "cpu_time",
+	CPURatio:            "cpu_ratio",
+	WriteError:          "write_error",
+}
+
+// withDefaults replaces empty names with their DefaultFieldNames values
+func (names FieldNames) withDefaults() FieldNames {
+	val := reflect.ValueOf(&names).Elem()
+	defaults := reflect.ValueOf(DefaultFieldNames)
+
+	for i := 0; i < val.NumField(); i++ {
+		if val.Field(i).Len() == 0 {
+			val.Field(i).Set(defaults.Field(i))
+		}
+	}
+
+	return names
+}
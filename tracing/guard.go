@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap"
+)
+
+// PathGuard is a middleware function that rejects requests with suspicious paths with a 400 response, and logs a
+// warning with the offending path. Paths are inspected after percent-decoding, so encoded attacks like %2e%2e%2f are
+// detected. A path is suspicious if any of its segments, separated by forward or back slashes, is exactly "..", or if
+// it contains control characters, e.g. null bytes. Names that merely contain dots, like "..." or "..config", are
+// allowed. PathGuard should be wrapped inside of Logger, so that its warnings carry request fields
+func PathGuard() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+			if reason, suspicious := suspiciousPath(req.URL.Path); suspicious {
+				logging.FromContext(req.Context()).Warn("suspicious request path",
+					logging.SafeString("suspicious_path", req.URL.Path),
+					zap.String("reason", reason))
+
+				http.Error(wr, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+
+			next.ServeHTTP(wr, req)
+		})
+	}
+}
+
+// suspiciousPath reports whether a decoded path contains traversal segments or control characters, and why
+func suspiciousPath(path string) (string, bool) {
+	if strings.ContainsFunc(path, func(r rune) bool { return r < 0x20 || r == 0x7f }) {
+		return "control character", true
+	}
+
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if segment == ".." {
+			return "traversal", true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,41 @@
+package tracing
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap"
+)
+
+// HandlerName wraps a handler when it is registered, e.g. with a ServeMux, to annotate the request's context logger
+// with a handler field naming it. Middleware functions can not see which handler a router will select, so handlers
+// must be wrapped individually. The name is resolved once, when HandlerName is called.
+//
+// Function handlers are named with runtime.FuncForPC, e.g. "example.com/pkg.GetUser". Anonymous functions and method
+// values are named by the compiler, e.g. "example.com/pkg.Routes.func1" and "example.com/pkg.(*API).Get-fm". Other
+// handlers are named by their type, e.g. "*pkg.API". The field is also added to the request scope with
+// logging.AddField, so that Logger's completion entry includes it
+func HandlerName(h http.Handler) http.Handler {
+	name := handlerName(h)
+
+	return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+		field := zap.String("handler", name)
+		logging.AddField(req.Context(), field)
+
+		ctx, _ := logging.With(req.Context(), field)
+		h.ServeHTTP(wr, req.WithContext(ctx))
+	})
+}
+
+// handlerName resolves a function handler's name, or a handler's type
+func handlerName(h http.Handler) string {
+	if fn, is := h.(http.HandlerFunc); is {
+		if info := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); info != nil {
+			return info.Name()
+		}
+	}
+
+	return reflect.TypeOf(h).String()
+}
@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RequireHeaders is a middleware function that rejects requests that are missing any of a set of headers with a 400
+// response, and logs a warning with a missing_headers field naming each absent header. Headers that are present with
+// an empty value are considered present. RequireHeaders should be wrapped inside of Logger, so that its warnings carry
+// request fields
+func RequireHeaders(names ...string) func(http.Handler) http.Handler {
+	required := make([]string, len(names))
+	for i, name := range names {
+		required[i] = http.CanonicalHeaderKey(name)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+			var missing []string
+
+			for _, name := range required {
+				if _, has := req.Header[name]; !has {
+					missing = append(missing, name)
+				}
+			}
+
+			if len(missing) > 0 {
+				logging.FromContext(req.Context()).Warn("missing required headers", zap.Strings("missing_headers", missing))
+
+				http.Error(wr, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+
+			next.ServeHTTP(wr, req)
+		})
+	}
+}
+
+// RedactedHeaders names request headers whose values are masked with logging.MaskFixed by LoggerWithHeaders. Names are
+// matched case-insensitively. It may be extended during initialization, before any requests are handled
+var RedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// loggedHeaders marshals the selected headers of a request as an object keyed by canonical header names
+type loggedHeaders struct {
+	header http.Header
+	names  []string
+}
+
+// headerValue joins a header's values, masking redacted headers. Missing headers are not ok
+func headerValue(header http.Header, name string) (string, bool) {
+	values, has := header[name]
+	if !has {
+		return "", false
+	}
+
+	if slices.ContainsFunc(RedactedHeaders, func(redacted string) bool { return strings.EqualFold(redacted, name) }) {
+		return logging.MaskFixed(""), true
+	}
+
+	return strings.Join(values, ", "), true
+}
+
+func (h loggedHeaders) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, name := range h.names {
+		if value, has := headerValue(h.header, name); has {
+			logging.SafeString(name, value).AddTo(enc)
+		}
+	}
+
+	return nil
+}
+
+// headerFields builds flat header_<name> fields for the selected headers of a request, e.g. header_user_agent
+func headerFields(header http.Header, names []string) []zap.Field {
+	var fields []zap.Field
+
+	for _, name := range names {
+		if value, has := headerValue(header, name); has {
+			key := "header_" + strings.ReplaceAll(strings.ToLower(name), "-", "_")
+			fields = append(fields, logging.SafeString(key, value))
+		}
+	}
+
+	return fields
+}
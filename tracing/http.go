@@ -7,10 +7,23 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"runtime"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/jmanero/go-logging"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type contextKeyType uint8
+
+const (
+	requestIDKey contextKeyType = iota
+	acceptedKey
+	spanIDKey
+	uncompressedKey
 )
 
 // BaseContext supplies a context for a listener with an annotated logger
@@ -20,13 +33,41 @@ func BaseContext(ctx context.Context, logger *zap.Logger) func(listener net.List
 	}
 }
 
-// ConnContext annotates a context logger for a connection
+// ConnContext annotates a context logger for a connection, and records the time that the connection was accepted, so
+// that Logger can log the first request on the connection with a queue_time field
 func ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	ctx = context.WithValue(ctx, acceptedKey, &accepted{at: time.Now()})
 	ctx, _ = logging.With(ctx, zap.Stringer("conn", conn.RemoteAddr()))
 
 	return ctx
 }
 
+// accepted records the time that a connection was accepted, and whether a request on it has consumed that time
+type accepted struct {
+	at      time.Time
+	claimed atomic.Bool
+}
+
+// Accepted retrieves the time that a request's connection was accepted, if the server was configured with ConnContext
+func Accepted(ctx context.Context) (time.Time, bool) {
+	conn, is := ctx.Value(acceptedKey).(*accepted)
+	if !is {
+		return time.Time{}, false
+	}
+
+	return conn.at, true
+}
+
+// claimAccepted retrieves the time that a request's connection was accepted, for only the first request on it
+func claimAccepted(ctx context.Context) (time.Time, bool) {
+	conn, is := ctx.Value(acceptedKey).(*accepted)
+	if !is || conn.claimed.Swap(true) {
+		return time.Time{}, false
+	}
+
+	return conn.at, true
+}
+
 // ReadCloserProxy accumulates the number of bytes read from an underlying Reader
 type ReadCloserProxy struct {
 	io.ReadCloser
@@ -42,28 +83,69 @@ func (p *ReadCloserProxy) Read(b []byte) (n int, err error) {
 	return
 }
 
-// ResponseWriterProxy captures the status code and body size of an HTTP response
+// ResponseWriterProxy captures the status code, body size, and first write error of an HTTP response. Handlers can
+// still use http.ResponseController through the proxy to flush responses, hijack connections, or set read and write
+// deadlines on the underlying connection, e.g. for long-polling endpoints
 type ResponseWriterProxy struct {
 	http.ResponseWriter
 
 	Status int
 	Size   int
+
+	// Wrote reports whether the response's final status has been written, by WriteHeader or by the first Write.
+	// Informational 1xx statuses do not set it
+	Wrote bool
+
+	// WriteError is the first error returned by the underlying ResponseWriter's Write method, e.g. when the client has
+	// closed its connection. The error is also returned to the handler
+	WriteError error
 }
 
 // WriteHeader captures the status code of an HTTP response
 func (p *ResponseWriterProxy) WriteHeader(status int) {
 	p.Status = status
+	p.Wrote = p.Wrote || status >= 200
 	p.ResponseWriter.WriteHeader(status)
 }
 
-// Write accumulates size of an HTTP response's body
+// Write accumulates size of an HTTP response's body, and records the first write error
 func (p *ResponseWriterProxy) Write(b []byte) (n int, err error) {
+	p.Wrote = true
 	n, err = p.ResponseWriter.Write(b)
 	p.Size += n
 
+	if err != nil && p.WriteError == nil {
+		p.WriteError = err
+	}
+
 	return
 }
 
+// Unwrap returns the underlying ResponseWriter, allowing http.ResponseController to use optional interfaces (e.g.
+// http.Flusher, http.Hijacker, and deadline setters) that it implements
+func (p *ResponseWriterProxy) Unwrap() http.ResponseWriter {
+	return p.ResponseWriter
+}
+
+// continueReader records whether the server sent a 100 Continue response to a request with an Expect: 100-continue
+// header. http.Server sends it when the body is first read, unless the final response has already been written
+type continueReader struct {
+	*ReadCloserProxy
+
+	writer *ResponseWriterProxy
+	read   bool
+	sent   bool
+}
+
+func (r *continueReader) Read(b []byte) (int, error) {
+	if !r.read {
+		r.read = true
+		r.sent = !r.writer.Wrote
+	}
+
+	return r.ReadCloserProxy.Read(b)
+}
+
 // GenerateID is a helper to generate a random identifier string
 func GenerateID() (string, error) {
 	var buf [32]byte
@@ -76,12 +158,104 @@ func GenerateID() (string, error) {
 	return hex.EncodeToString(buf[:]), nil
 }
 
-// Identifier is a middleware function that ensures an X-Request-ID header is present on the request context
+// IdentifierOption configures the middleware function returned by NewIdentifier
+type IdentifierOption func(*identifierOptions)
+
+type identifierOptions struct {
+	header    string
+	maxLength int
+	response  bool
+	names     FieldNames
+}
+
+// DefaultMaxIDLength is the default maximum length of request identifiers that are accepted from clients
+const DefaultMaxIDLength = 128
+
+var defaultIdentifierOptions = identifierOptions{
+	header:    RequestIDHeader,
+	maxLength: DefaultMaxIDLength,
+	response:  true,
+	names:     DefaultFieldNames,
+}
+
+// RequestIDHeader is the default header used to propagate request identifiers
+const RequestIDHeader = "X-Request-ID"
+
+// IdentifierWithHeader sets the name of the header used to read, propagate, and return request identifiers. The
+// Transport RoundTripper uses the same header name to propagate identifiers to outbound requests
+func IdentifierWithHeader(name string) IdentifierOption {
+	return func(opts *identifierOptions) {
+		opts.header = http.CanonicalHeaderKey(name)
+	}
+}
+
+// IdentifierWithResponseHeader controls whether the request identifier is returned to the client in a response header,
+// named by IdentifierWithHeader. The identifier is still attached to the context logger and upstream request when
+// disabled
+func IdentifierWithResponseHeader(enabled bool) IdentifierOption {
+	return func(opts *identifierOptions) {
+		opts.response = enabled
+	}
+}
+
+// IdentifierWithFieldNames sets the keys of the fields that Identifier and Span emit
+func IdentifierWithFieldNames(names FieldNames) IdentifierOption {
+	return func(opts *identifierOptions) {
+		opts.names = names.withDefaults()
+	}
+}
+
+// IdentifierWithMaxLength sets the maximum length of request identifiers that are accepted from clients. Longer
+// identifiers are replaced with a generated identifier
+func IdentifierWithMaxLength(n int) IdentifierOption {
+	return func(opts *identifierOptions) {
+		opts.maxLength = n
+	}
+}
+
+// SanitizeID removes all characters other than printable, non-space ASCII from an identifier that was received from a
+// client, so that it is safe to log and to return in a response header. It returns false if the sanitized identifier
+// is empty or longer than max, in which case it should be replaced
+func SanitizeID(id string, max int) (string, bool) {
+	clean := strings.Map(func(r rune) rune {
+		if r > ' ' && r < 0x7f {
+			return r
+		}
+
+		return -1
+	}, id)
+
+	if len(clean) == 0 || len(clean) > max {
+		return "", false
+	}
+
+	return clean, true
+}
+
+// Identifier is a middleware function that ensures a request identifier header, X-Request-ID by default, is present on
+// the request context. Identifiers received from clients are sanitized with SanitizeID, and replaced if they are
+// invalid
 func Identifier(next http.Handler) http.HandlerFunc {
+	return identifier(next, defaultIdentifierOptions)
+}
+
+// NewIdentifier builds an Identifier middleware function with additional options
+func NewIdentifier(opts ...IdentifierOption) func(http.Handler) http.Handler {
+	options := defaultIdentifierOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return identifier(next, options)
+	}
+}
+
+func identifier(next http.Handler, options identifierOptions) http.HandlerFunc {
 	return func(wr http.ResponseWriter, req *http.Request) {
 		// Try to use an existing tracing ID from downstream
-		id := req.Header.Get("X-Request-ID")
-		if len(id) == 0 {
+		id, valid := SanitizeID(req.Header.Get(options.header), options.maxLength)
+		if !valid {
 			var err error
 
 			// Generate a new tracing identifier
@@ -89,43 +263,447 @@ func Identifier(next http.Handler) http.HandlerFunc {
 			if err != nil {
 				panic(err)
 			}
-
-			// Ensure that the generated X-Request-ID header is included in upstream requests
-			req.Header.Set("X-Request-ID", id)
 		}
 
+		// Ensure that the generated or sanitized X-Request-ID header is included in upstream requests
+		req.Header.Set(options.header, id)
+
 		// Ensure that the downstream response contains the X-Request-ID header
-		wr.Header().Set("X-Request-ID", id)
+		if options.response {
+			wr.Header().Set(options.header, id)
+		}
 
-		ctx, _ := logging.With(req.Context(), zap.String("id", id))
+		ctx := context.WithValue(req.Context(), requestIDKey, requestID{header: options.header, id: id})
+		ctx, _ = logging.With(ctx, zap.String(options.names.ID, id))
 		next.ServeHTTP(wr, req.WithContext(ctx))
 	}
 }
 
+// requestID stores a request identifier with the name of the header that it is propagated in
+type requestID struct {
+	header string
+	id     string
+}
+
+// RequestID retrieves the request identifier attached to a Context by the Identifier middleware
+func RequestID(ctx context.Context) (string, bool) {
+	rid, is := ctx.Value(requestIDKey).(requestID)
+	return rid.id, is
+}
+
+// Scheme determines the scheme that a client used to make a request. Signals are checked in order of precedence:
+//
+//  1. https, if the request was received on a TLS connection
+//  2. The first value of an X-Forwarded-Proto header, set by TLS-terminating proxies. Clients can also set this
+//     header, so servers that are directly reachable should strip it
+//  3. The scheme of an absolute request URI, e.g. from a forward proxy request
+//  4. http
+func Scheme(req *http.Request) string {
+	if req.TLS != nil {
+		return "https"
+	}
+
+	if proto := req.Header.Get("X-Forwarded-Proto"); len(proto) > 0 {
+		proto, _, _ = strings.Cut(proto, ",")
+		return strings.ToLower(strings.TrimSpace(proto))
+	}
+
+	if len(req.URL.Scheme) > 0 {
+		return req.URL.Scheme
+	}
+
+	return "http"
+}
+
+// LoggerOption configures the middleware function returned by NewLogger
+type LoggerOption func(*loggerOptions)
+
+type loggerOptions struct {
+	names   FieldNames
+	access  *accessLog
+	millis  bool
+	extra   func(*http.Request) []zap.Field
+	start   bool
+	header  bool
+	cookies bool
+	content bool
+
+	headers      []string
+	headerObject bool
+
+	notAllowed      bool
+	notAllowedLevel zapcore.Level
+
+	largeResponse int
+	cpuTime       bool
+	pathValues    []string
+	slowStack     time.Duration
+}
+
+// LoggerWithAccessLog writes a line in the given AccessLogFormat to w for each request, alongside the structured log
+// entry. Lines are written under a lock, so w does not need to be safe for concurrent use
+func LoggerWithAccessLog(w io.Writer, format AccessLogFormat) LoggerOption {
+	return func(opts *loggerOptions) {
+		opts.access = &accessLog{w: w, format: format}
+	}
+}
+
+// LoggerWithDurationMillis replaces the completion entry's duration field with an integer duration_ms field
+func LoggerWithDurationMillis() LoggerOption {
+	return func(opts *loggerOptions) {
+		opts.millis = true
+	}
+}
+
+// LoggerWithExtraFields adds the fields returned by fn to the completion entry. fn is called after the handler has
+// returned with the request that was passed to the handler. Handlers can not replace that request's Context, so values
+// that a handler contributes must be stored in a mutable holder that was added to the Context before the handler was
+// called, e.g. by an outer middleware function
+func LoggerWithExtraFields(fn func(*http.Request) []zap.Field) LoggerOption {
+	return func(opts *loggerOptions) {
+		opts.extra = fn
+	}
+}
+
+// LoggerWithStartEntry logs an additional debug-level entry before calling the handler, so that requests which never
+// complete can be identified by a start entry without a matching completion entry. This doubles the number of entries
+// logged for each request when debug level is enabled
+func LoggerWithStartEntry() LoggerOption {
+	return func(opts *loggerOptions) {
+		opts.start = true
+	}
+}
+
+// LoggerWithHeaderStats adds the number of request and response header lines and their approximate size on the wire,
+// as req_headers, req_header_size, res_headers, and res_header_size fields, to the completion entry
+func LoggerWithHeaderStats() LoggerOption {
+	return func(opts *loggerOptions) {
+		opts.header = true
+	}
+}
+
+// headerStats counts the lines in a header, and their size as "Key: Value\r\n" lines
+func headerStats(header http.Header) (count, size int) {
+	for key, values := range header {
+		for _, value := range values {
+			count++
+			size += len(key) + len(value) + 4
+		}
+	}
+
+	return
+}
+
+// LoggerWithCookieNames adds the names of the cookies sent with a request to the completion entry as a cookies field.
+// Cookie values are never logged
+func LoggerWithCookieNames() LoggerOption {
+	return func(opts *loggerOptions) {
+		opts.cookies = true
+	}
+}
+
+// LoggerWithHeaders adds the values of the named request headers to the completion entry, as a flat field for each
+// header, e.g. header_user_agent, or as a single nested object with LoggerWithHeaderObject. Multiple values are joined
+// with commas, and missing headers are omitted. The values of RedactedHeaders are masked. No headers are logged by
+// default
+func LoggerWithHeaders(names ...string) LoggerOption {
+	return func(opts *loggerOptions) {
+		for _, name := range names {
+			opts.headers = append(opts.headers, http.CanonicalHeaderKey(name))
+		}
+	}
+}
+
+// LoggerWithHeaderObject logs the headers selected by LoggerWithHeaders as a single headers object keyed by canonical
+// header names, e.g. {"headers": {"User-Agent": "curl/8.0"}}, instead of flat fields
+func LoggerWithHeaderObject() LoggerOption {
+	return func(opts *loggerOptions) {
+		opts.headerObject = true
+	}
+}
+
+// LoggerWithMethodNotAllowed flags completion entries for 405 Method Not Allowed responses, which often indicate client
+// bugs or probing, with a method_not_allowed field, and logs them at lvl instead of info level
+func LoggerWithMethodNotAllowed(lvl zapcore.Level) LoggerOption {
+	return func(opts *loggerOptions) {
+		opts.notAllowed = true
+		opts.notAllowedLevel = lvl
+	}
+}
+
+// LoggerWithContentTypes adds the request's Accept header and the response's Content-Type header to the completion
+// entry as accept and content_type fields, to help diagnose content negotiation. Each field is omitted if its header is
+// empty
+func LoggerWithContentTypes() LoggerOption {
+	return func(opts *loggerOptions) {
+		opts.content = true
+	}
+}
+
+// LoggerWithLargeResponse flags completion entries for responses with bodies larger than threshold bytes, which can
+// indicate missing pagination, with a large_response field, and logs them at warn level or higher. The size is
+// compared with the res_size field, so compressed responses are measured by their compressed size. A threshold of zero
+// or less disables the check, which is the default
+func LoggerWithLargeResponse(threshold int) LoggerOption {
+	return func(opts *loggerOptions) {
+		opts.largeResponse = threshold
+	}
+}
+
+// LoggerWithCPUTime adds cpu_time and cpu_ratio fields to completion entries, with the CPU time that the process
+// consumed while the request was handled and its ratio to the request's wall time, to help distinguish slow handlers
+// from a saturated scheduler or GC pauses. CPU time is sampled with getrusage(2) for the whole process, so concurrent
+// requests contribute to each other's measurements, and ratios above one are expected for busy servers. The fields are
+// omitted on platforms without getrusage
+func LoggerWithCPUTime() LoggerOption {
+	return func(opts *loggerOptions) {
+		opts.cpuTime = true
+	}
+}
+
+// LoggerWithPathValues adds a path_values object to completion entries with the values of named path wildcards, keyed
+// by their names, e.g. {"path_values": {"id": "42"}} for a "/users/{id}" pattern. Nesting the values keeps wildcard
+// names from colliding with other fields, like Identifier's id. Values are read with http.Request.PathValue after the
+// handler returns, so Logger must wrap the http.ServeMux that matches the request's pattern. Missing and empty values
+// are omitted, and the object is omitted if none are present
+func LoggerWithPathValues(names ...string) LoggerOption {
+	return func(opts *loggerOptions) {
+		opts.pathValues = append(opts.pathValues, names...)
+	}
+}
+
+// LoggerWithSlowStack logs a warn-level entry with the stacks of every goroutine in the process, from runtime.Stack, if
+// a request is still being handled after threshold, to help diagnose hung or slow handlers. The request's goroutine can
+// not be identified from the watcher, so its stack must be found by its handler's frames. Capturing stacks stops the
+// world, and at most MaxSlowStackSize bytes are logged. The watcher is stopped when the handler returns. A threshold of
+// zero or less disables the watcher, which is the default
+func LoggerWithSlowStack(threshold time.Duration) LoggerOption {
+	return func(opts *loggerOptions) {
+		opts.slowStack = threshold
+	}
+}
+
+// MaxSlowStackSize is the maximum number of bytes of goroutine stacks logged by LoggerWithSlowStack
+const MaxSlowStackSize = 1 << 20
+
+// LoggerWithFieldNames sets the keys of the fields that Logger emits
+func LoggerWithFieldNames(names FieldNames) LoggerOption {
+	return func(opts *loggerOptions) {
+		opts.names = names.withDefaults()
+	}
+}
+
 // Logger is a middleware function that injects request information into the request's context logger, then logs HTTP
-// request/response information after the request has completed
+// request/response information after the request has completed. FieldNames describes the fields that it logs
 func Logger(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
-		ctx, logger := logging.Named(req.Context(), "request",
-			zap.String("host", req.Host),
-			zap.String("proto", req.Proto),
-			zap.String("method", req.Method),
-			zap.String("path", req.RequestURI))
-
-		// Wrap request reader and response writer in observable proxies
-		reader := &ReadCloserProxy{ReadCloser: req.Body}
-		writer := &ResponseWriterProxy{ResponseWriter: wr, Status: http.StatusOK}
-		start := time.Now()
-
-		req.Body = reader
-
-		next.ServeHTTP(writer, req.WithContext(ctx))
-
-		logger.Info("request completed",
-			zap.Int("req_size", reader.Size),
-			zap.Int("status", writer.Status),
-			zap.Int("res_size", writer.Size),
-			zap.Duration("duration", time.Since(start)),
-		)
-	})
+	return NewLogger()(next)
+}
+
+// NewLogger builds a Logger middleware function with additional options. Each request's Context is given a
+// request-scoped state holder with logging.WithScope. If a handler records an error with logging.SetError, the
+// completion entry includes it and is logged at error level. Fields that handlers accumulate with logging.AddField are
+// merged into the completion entry, for a single wide entry per request
+func NewLogger(opts ...LoggerOption) func(http.Handler) http.Handler {
+	options := loggerOptions{names: DefaultFieldNames}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+			names := options.names
+			requestFields := []zap.Field{
+				logging.SafeString(names.Host, req.Host),
+				logging.SafeString(names.Scheme, Scheme(req)),
+				logging.SafeString(names.Proto, req.Proto),
+				logging.SafeString(names.Method, req.Method),
+				logging.SafeString(names.Path, req.RequestURI),
+			}
+
+			// Identify the local address of the connection for servers with multiple listeners or interfaces
+			if local, is := req.Context().Value(http.LocalAddrContextKey).(net.Addr); is && local != nil {
+				requestFields = append(requestFields, zap.String(names.Local, local.String()))
+			}
+
+			if req.TLS != nil && len(req.TLS.NegotiatedProtocol) > 0 {
+				requestFields = append(requestFields, logging.SafeString(names.ALPN, req.TLS.NegotiatedProtocol))
+			}
+
+			ctx, logger := logging.Named(logging.WithScope(req.Context()), "request", requestFields...)
+
+			// Wrap request reader and response writer in observable proxies
+			reader := &ReadCloserProxy{ReadCloser: req.Body}
+			writer := &ResponseWriterProxy{ResponseWriter: wr, Status: http.StatusOK}
+			start := time.Now()
+
+			// Provide a holder for the Uncompressed middleware
+			uncompressed := &uncompressedSize{}
+			ctx = context.WithValue(ctx, uncompressedKey, uncompressed)
+
+			req.Body = reader
+
+			// Observe whether reading the body triggers the server's 100 Continue response
+			var expect *continueReader
+			if strings.EqualFold(req.Header.Get("Expect"), "100-continue") {
+				expect = &continueReader{ReadCloserProxy: reader, writer: writer}
+				req.Body = expect
+			}
+			inner := req.WithContext(ctx)
+
+			if options.start {
+				logger.Debug("request started")
+			}
+
+			var slowTimer *time.Timer
+			if options.slowStack > 0 {
+				slowTimer = time.AfterFunc(options.slowStack, func() {
+					buf := make([]byte, MaxSlowStackSize)
+					n := runtime.Stack(buf, true)
+
+					logger.Warn("slow request stacks", logging.Elapsed(start), zap.ByteString("stacks", buf[:n]),
+						zap.Bool("stacks_truncated", n == len(buf)))
+				})
+			}
+
+			var cpuStart time.Duration
+			cpuSampled := false
+			if options.cpuTime {
+				cpuStart, cpuSampled = processCPUTime()
+			}
+
+			next.ServeHTTP(writer, inner)
+
+			if slowTimer != nil {
+				slowTimer.Stop()
+			}
+
+			lvl := zapcore.InfoLevel
+			fields := []zap.Field{
+				zap.Int(names.ReqSize, reader.Size),
+				zap.Int(names.Status, writer.Status),
+				zap.Int(names.ResSize, writer.Size),
+			}
+
+			if options.millis {
+				fields = append(fields, zap.Int64(names.DurationMillis, time.Since(start).Milliseconds()))
+			} else {
+				fields = append(fields, zap.Duration(names.Duration, time.Since(start)))
+			}
+
+			if encoding := wr.Header().Get("Content-Encoding"); len(encoding) > 0 {
+				fields = append(fields, zap.String(names.ResEncoding, encoding))
+
+				if uncompressed.set && writer.Size > 0 {
+					fields = append(fields,
+						zap.Int(names.ResSizeUncompressed, uncompressed.size),
+						zap.Float64(names.CompressionRatio, float64(uncompressed.size)/float64(writer.Size)))
+				}
+			}
+
+			if accepted, is := claimAccepted(ctx); is {
+				fields = append(fields, zap.Duration(names.QueueTime, start.Sub(accepted)))
+			}
+
+			if expect != nil {
+				fields = append(fields, zap.Bool(names.ExpectContinue, true), zap.Bool(names.ContinueSent, expect.sent))
+			}
+
+			if cpuSampled {
+				if cpuEnd, ok := processCPUTime(); ok {
+					cpu := cpuEnd - cpuStart
+					fields = append(fields, zap.Duration(names.CPUTime, cpu))
+
+					if wall := time.Since(start); wall > 0 {
+						fields = append(fields, zap.Float64(names.CPURatio, float64(cpu)/float64(wall)))
+					}
+				}
+			}
+
+			if options.header {
+				reqCount, reqSize := headerStats(req.Header)
+				resCount, resSize := headerStats(wr.Header())
+
+				fields = append(fields,
+					zap.Int(names.ReqHeaders, reqCount),
+					zap.Int(names.ReqHeaderSize, reqSize),
+					zap.Int(names.ResHeaders, resCount),
+					zap.Int(names.ResHeaderSize, resSize))
+			}
+
+			if options.content {
+				if accept := req.Header.Get("Accept"); len(accept) > 0 {
+					fields = append(fields, logging.SafeString(names.Accept, accept))
+				}
+
+				// Read after the handler has returned, once the response's headers are complete
+				if contentType := wr.Header().Get("Content-Type"); len(contentType) > 0 {
+					fields = append(fields, zap.String(names.ContentType, contentType))
+				}
+			}
+
+			if options.cookies {
+				if cookies := req.Cookies(); len(cookies) > 0 {
+					cookieNames := make([]string, len(cookies))
+					for i, cookie := range cookies {
+						cookieNames[i] = cookie.Name
+					}
+
+					fields = append(fields, zap.Strings(names.Cookies, cookieNames))
+				}
+			}
+
+			if len(options.headers) > 0 {
+				if options.headerObject {
+					fields = append(fields, zap.Object(names.Headers, loggedHeaders{header: req.Header, names: options.headers}))
+				} else {
+					fields = append(fields, headerFields(req.Header, options.headers)...)
+				}
+			}
+
+			var pathValues []zap.Field
+			for _, name := range options.pathValues {
+				if value := inner.PathValue(name); len(value) > 0 {
+					pathValues = append(pathValues, logging.SafeString(name, value))
+				}
+			}
+
+			if len(pathValues) > 0 {
+				fields = append(fields, zap.Dict(names.PathValues, pathValues...))
+			}
+
+			if options.extra != nil {
+				fields = append(fields, options.extra(inner)...)
+			}
+
+			// Merge fields accumulated by handlers with logging.AddField into a single wide entry
+			fields = append(fields, logging.AccumulatedFields(ctx)...)
+
+			if options.notAllowed && writer.Status == http.StatusMethodNotAllowed {
+				lvl = options.notAllowedLevel
+				fields = append(fields, zap.Bool(names.MethodNotAllowed, true))
+			}
+
+			if options.largeResponse > 0 && writer.Size > options.largeResponse {
+				lvl = max(lvl, zapcore.WarnLevel)
+				fields = append(fields, zap.Bool(names.LargeResponse, true))
+			}
+
+			// Distinguish responses that could not be delivered, e.g. because the client aborted the connection
+			if writer.WriteError != nil {
+				lvl = max(lvl, zapcore.WarnLevel)
+				fields = append(fields, zap.NamedError(names.WriteError, writer.WriteError))
+			}
+
+			if err := logging.ScopeError(ctx); err != nil {
+				lvl = zapcore.ErrorLevel
+				fields = append(fields, zap.NamedError(names.Error, err))
+			}
+
+			logger.Log(lvl, "request completed", fields...)
+
+			if options.access != nil {
+				options.access.write(req, writer, start)
+			}
+		})
+	}
 }
@@ -0,0 +1,165 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// serve runs a handler behind Logger on a test server, and returns the error that the handler reported
+func serve(t *testing.T, handler func(http.ResponseWriter, *http.Request) error, body io.Reader) error {
+	t.Helper()
+
+	result := make(chan error, 1)
+	srv := httptest.NewUnstartedServer(Logger(http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+		result <- handler(wr, req)
+	})))
+
+	srv.Config.BaseContext = func(net.Listener) context.Context {
+		return logging.WithLogger(context.Background(), zaptest.NewLogger(t))
+	}
+
+	srv.Start()
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		if res, err := srv.Client().Do(req); err == nil {
+			res.Body.Close()
+		}
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler did not complete")
+		return nil
+	}
+}
+
+func TestResponseWriterProxyReadDeadline(t *testing.T) {
+	// The client never sends a body, so reads block until the deadline
+	body, pw := io.Pipe()
+	defer pw.Close()
+
+	err := serve(t, func(wr http.ResponseWriter, req *http.Request) error {
+		if err := http.NewResponseController(wr).SetReadDeadline(time.Now().Add(50 * time.Millisecond)); err != nil {
+			return err
+		}
+
+		_, err := io.ReadAll(req.Body)
+		return err
+	}, body)
+
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("expected a deadline error from the request body, got %v", err)
+	}
+}
+
+func TestResponseWriterProxyWriteDeadline(t *testing.T) {
+	err := serve(t, func(wr http.ResponseWriter, _ *http.Request) error {
+		if err := http.NewResponseController(wr).SetWriteDeadline(time.Now().Add(-time.Second)); err != nil {
+			return err
+		}
+
+		// Write more than the server buffers, so that the connection is written to
+		chunk := []byte(strings.Repeat("x", 64<<10))
+		for i := 0; i < 64; i++ {
+			if _, err := wr.Write(chunk); err != nil {
+				return err
+			}
+		}
+
+		return http.NewResponseController(wr).Flush()
+	}, nil)
+
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("expected a deadline error from the response writer, got %v", err)
+	}
+}
+
+func TestQueueTimeFirstRequest(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	srv := httptest.NewUnstartedServer(Logger(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
+	srv.Config.BaseContext = func(net.Listener) context.Context {
+		return logging.New(context.Background(), core)
+	}
+
+	srv.Config.ConnContext = ConnContext
+	srv.Start()
+	defer srv.Close()
+
+	// Both requests reuse the client's keep-alive connection
+	for i := 0; i < 2; i++ {
+		res, err := srv.Client().Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+	}
+
+	entries := logs.FilterMessage("request completed").All()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 completion entries, got %d", len(entries))
+	}
+
+	if entries[0].ContextMap()["conn"] != entries[1].ContextMap()["conn"] {
+		t.Fatal("expected both requests to use the same connection")
+	}
+
+	if _, has := entries[0].ContextMap()["queue_time"]; !has {
+		t.Error("expected the first request on the connection to include queue_time")
+	}
+
+	if _, has := entries[1].ContextMap()["queue_time"]; has {
+		t.Error("expected the second request on the connection not to include queue_time")
+	}
+}
+
+func FuzzSanitizeID(f *testing.F) {
+	f.Add("a1b2c3", 64)
+	f.Add("id with spaces\r\nX-Injected: true", 64)
+	f.Add("\xff\xfe\x00id", 8)
+	f.Add("", 0)
+
+	f.Fuzz(func(t *testing.T, id string, max int) {
+		clean, ok := SanitizeID(id, max)
+		if !ok {
+			if len(clean) > 0 {
+				t.Fatalf("rejected identifier returned %q", clean)
+			}
+
+			return
+		}
+
+		if len(clean) == 0 || len(clean) > max {
+			t.Fatalf("accepted identifier %q has length %d, with max %d", clean, len(clean), max)
+		}
+
+		for i := 0; i < len(clean); i++ {
+			if c := clean[i]; c <= ' ' || c >= 0x7f {
+				t.Fatalf("accepted identifier %q contains byte %#x", clean, c)
+			}
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package tracing
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap"
+)
+
+// InFlightCounter counts the requests that are currently being handled by its Handler middleware function
+type InFlightCounter struct {
+	count atomic.Int64
+}
+
+// InFlight creates a new InFlightCounter
+func InFlight() *InFlightCounter {
+	return &InFlightCounter{}
+}
+
+// Handler is a middleware function that counts requests while they are being handled, and annotates the request's
+// context logger with an in_flight field with the number of requests being handled, including itself, when it started
+func (c *InFlightCounter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+		n := c.count.Add(1)
+		defer c.count.Add(-1)
+
+		ctx, _ := logging.With(req.Context(), zap.Int64("in_flight", n))
+		next.ServeHTTP(wr, req.WithContext(ctx))
+	})
+}
+
+// Current returns the number of requests that are currently being handled
+func (c *InFlightCounter) Current() int64 {
+	return c.count.Load()
+}
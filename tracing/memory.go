@@ -0,0 +1,48 @@
+package tracing
+
+import (
+	"net/http"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// MemStats is a debugging middleware function that samples runtime memory statistics around one of every n requests,
+// and logs the difference at debug level. Requests are only sampled when the context logger has debug level enabled.
+//
+// runtime.ReadMemStats stops the world on each call, so sampled requests pause every goroutine in the process twice.
+// The statistics are process-wide, so concurrent requests and garbage collection contribute to each sample. The
+// total_alloc_delta field, counting bytes allocated while the handler ran, is generally more useful than
+// heap_alloc_delta, which shrinks when a collection runs. Do not enable this in production
+func MemStats(n uint64) func(http.Handler) http.Handler {
+	if n == 0 {
+		n = 1
+	}
+
+	var count atomic.Uint64
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+			logger := logging.FromContext(req.Context())
+			if count.Add(1)%n != 0 || !logger.Core().Enabled(zapcore.DebugLevel) {
+				next.ServeHTTP(wr, req)
+				return
+			}
+
+			var before, after runtime.MemStats
+
+			runtime.ReadMemStats(&before)
+			next.ServeHTTP(wr, req)
+			runtime.ReadMemStats(&after)
+
+			logger.Debug("request memory",
+				zap.Int64("heap_alloc_delta", int64(after.HeapAlloc)-int64(before.HeapAlloc)),
+				zap.Uint64("total_alloc_delta", after.TotalAlloc-before.TotalAlloc),
+				zap.Uint64("mallocs_delta", after.Mallocs-before.Mallocs),
+			)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package tracing
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// MaxMultipartParts is the maximum number of parts that Multipart describes in its entry
+const MaxMultipartParts = 64
+
+// Multipart is a middleware function that logs the parts of multipart/form-data request bodies, with their form names,
+// file names, content types, and sizes. Part contents are never logged.
+//
+// The body is not buffered or consumed by the middleware. Bytes are copied to a parser in a separate goroutine as the
+// handler reads them, and the parts are logged after the handler returns, so only the parts that the handler has read
+// are included. Sizes are counted from the encoded part bodies, as multipart requests do not declare part sizes. At
+// most MaxMultipartParts parts are described: the parts field counts every part, and a truncated field is added when
+// some are not described
+func Multipart(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+		mediatype, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if err != nil || mediatype != "multipart/form-data" || len(params["boundary"]) == 0 {
+			next.ServeHTTP(wr, req)
+			return
+		}
+
+		reader, writer := io.Pipe()
+		result := make(chan multipartScan, 1)
+
+		go func() {
+			result <- scanMultipart(reader, params["boundary"])
+		}()
+
+		req.Body = &teeBody{ReadCloser: req.Body, w: writer}
+
+		next.ServeHTTP(wr, req)
+
+		writer.Close()
+		scan := <-result
+
+		fields := []zap.Field{zap.Int("parts", scan.count), zap.Array("part_info", scan.parts)}
+		if scan.count > len(scan.parts) {
+			fields = append(fields, zap.Bool("truncated", true))
+		}
+
+		logging.FromContext(req.Context()).Info("multipart request body", fields...)
+	})
+}
+
+// teeBody copies bytes to a writer as they are read from a request body
+type teeBody struct {
+	io.ReadCloser
+
+	w io.Writer
+}
+
+func (b *teeBody) Read(p []byte) (n int, err error) {
+	n, err = b.ReadCloser.Read(p)
+	if n > 0 {
+		b.w.Write(p[:n])
+	}
+
+	return
+}
+
+type multipartPart struct {
+	name        string
+	filename    string
+	contentType string
+	size        int64
+}
+
+func (p *multipartPart) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", p.name)
+
+	if len(p.filename) > 0 {
+		enc.AddString("filename", p.filename)
+	}
+
+	if len(p.contentType) > 0 {
+		enc.AddString("content_type", p.contentType)
+	}
+
+	enc.AddInt64("size", p.size)
+	return nil
+}
+
+type multipartParts []*multipartPart
+
+func (parts multipartParts) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, part := range parts {
+		if err := enc.AppendObject(part); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// multipartScan describes up to MaxMultipartParts parts of a multipart body, and counts all of them
+type multipartScan struct {
+	parts multipartParts
+	count int
+}
+
+// scanMultipart reads part headers and counts part sizes from a multipart body. The reader is always drained, so that
+// writes to it never block, even if the body is malformed
+func scanMultipart(r io.Reader, boundary string) (scan multipartScan) {
+	mr := multipart.NewReader(r, boundary)
+
+	for {
+		part, err := mr.NextRawPart()
+		if err != nil {
+			break
+		}
+
+		size, _ := io.Copy(io.Discard, part)
+
+		scan.count++
+		if len(scan.parts) == MaxMultipartParts {
+			continue
+		}
+
+		scan.parts = append(scan.parts, &multipartPart{
+			name:        part.FormName(),
+			filename:    part.FileName(),
+			contentType: part.Header.Get("Content-Type"),
+			size:        size,
+		})
+	}
+
+	io.Copy(io.Discard, r)
+	return
+}
@@ -0,0 +1,114 @@
+// Package otelmetrics records HTTP server metrics with the OpenTelemetry metrics API. It is a separate package so that
+// the tracing package does not depend upon OpenTelemetry
+package otelmetrics
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jmanero/go-logging"
+	"github.com/jmanero/go-logging/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// OTelMetrics builds a middleware function that records the number of completed requests, a histogram of request
+// durations in seconds, and the number of active requests with instruments created from a Meter. Measurements are
+// attributed with the request method and the response status class (e.g. 2xx), captured by a
+// tracing.ResponseWriterProxy. Methods other than those defined by RFC 9110 and RFC 5789 are recorded as _OTHER to
+// bound attribute cardinality. Tags recorded for the request with logging.Tag are added as attributes to the request
+// count and duration measurements
+func OTelMetrics(meter metric.Meter) (func(http.Handler) http.Handler, error) {
+	requests, err := meter.Int64Counter("http.server.requests",
+		metric.WithDescription("Number of completed HTTP requests"),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return nil, err
+	}
+
+	duration, err := meter.Float64Histogram("http.server.request.duration",
+		metric.WithDescription("Duration of HTTP requests"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := meter.Int64UpDownCounter("http.server.active_requests",
+		metric.WithDescription("Number of HTTP requests that are currently being handled"),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+			// Share a request scope with an inner Logger middleware to read the request's tags
+			ctx := logging.WithScope(req.Context())
+			req = req.WithContext(ctx)
+
+			method := attribute.String("http.request.method", Method(req.Method))
+
+			active.Add(ctx, 1, metric.WithAttributes(method))
+			defer active.Add(ctx, -1, metric.WithAttributes(method))
+
+			writer := &tracing.ResponseWriterProxy{ResponseWriter: wr, Status: http.StatusOK}
+			start := time.Now()
+
+			next.ServeHTTP(writer, req)
+
+			attrs := metric.WithAttributes(append(tagAttributes(ctx), method,
+				attribute.String("http.response.status_class", StatusClass(writer.Status)))...)
+
+			requests.Add(ctx, 1, attrs)
+			duration.Record(ctx, time.Since(start).Seconds(), attrs)
+		})
+	}, nil
+}
+
+// Method normalizes a request method for use as an attribute value
+func Method(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+		http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+
+	return "_OTHER"
+}
+
+// StatusClass formats the class of a response status code, e.g. 2xx
+func StatusClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// Timings builds a logging.TimingObserver that records the durations of operations measured by logging.StartOp and
+// logging.Phase in a histogram created from a Meter, in seconds, attributed with the name of each operation's Logger.
+// Add it to a Context with logging.WithTimings
+func Timings(meter metric.Meter) (logging.TimingObserver, error) {
+	duration, err := meter.Float64Histogram("logging.operation.duration",
+		metric.WithDescription("Duration of named logging operations"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(name string, d time.Duration) {
+		duration.Record(context.Background(), d.Seconds(), metric.WithAttributes(attribute.String("logger.name", name)))
+	}, nil
+}
+
+// tagAttributes converts the tags recorded in a request scope to attributes, sorted by key
+func tagAttributes(ctx context.Context) []attribute.KeyValue {
+	tags := logging.Tags(ctx)
+	attrs := make([]attribute.KeyValue, 0, len(tags)+2)
+
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		attrs = append(attrs, attribute.String(key, tags[key]))
+	}
+
+	return attrs
+}
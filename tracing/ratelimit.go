@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"container/list"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ClientLimit is a middleware function that demotes entries logged for a client's requests to debug level once it has
+// made more than threshold requests within a window. Error level and higher entries are never demoted. Clients are
+// identified by remote IP address, and the most recently seen size clients are tracked.
+//
+// ClientLimit replaces the context logger, so it must be wrapped outside of Logger for Logger's entries to be demoted
+func ClientLimit(size, threshold int, window time.Duration) func(http.Handler) http.Handler {
+	clients := &clientLRU{size: size, window: window, items: make(map[string]*list.Element)}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+			host, _, err := net.SplitHostPort(req.RemoteAddr)
+			if err != nil {
+				host = req.RemoteAddr
+			}
+
+			if clients.hit(host, time.Now()) > threshold {
+				ctx, _ := logging.WithOptions(req.Context(), zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+					return &demoteCore{Core: core}
+				}))
+
+				req = req.WithContext(ctx)
+			}
+
+			next.ServeHTTP(wr, req)
+		})
+	}
+}
+
+// clientLRU counts requests from recently seen clients in fixed windows
+type clientLRU struct {
+	sync.Mutex
+
+	size   int
+	window time.Duration
+	order  list.List
+	items  map[string]*list.Element
+}
+
+type clientCount struct {
+	host  string
+	start time.Time
+	count int
+}
+
+// hit records a request from a client and returns its count in the current window
+func (l *clientLRU) hit(host string, now time.Time) int {
+	l.Lock()
+	defer l.Unlock()
+
+	if elem, has := l.items[host]; has {
+		l.order.MoveToFront(elem)
+
+		client := elem.Value.(*clientCount)
+		if now.Sub(client.start) >= l.window {
+			client.start = now
+			client.count = 0
+		}
+
+		client.count++
+		return client.count
+	}
+
+	l.items[host] = l.order.PushFront(&clientCount{host: host, start: now, count: 1})
+
+	for l.order.Len() > l.size && l.order.Len() > 1 {
+		delete(l.items, l.order.Remove(l.order.Back()).(*clientCount).host)
+	}
+
+	return 1
+}
+
+// demoteCore writes entries below error level at debug level
+type demoteCore struct {
+	zapcore.Core
+}
+
+func (c *demoteCore) Enabled(lvl zapcore.Level) bool {
+	if lvl < zapcore.ErrorLevel {
+		lvl = zapcore.DebugLevel
+	}
+
+	return c.Core.Enabled(lvl)
+}
+
+func (c *demoteCore) With(fields []zapcore.Field) zapcore.Core {
+	return &demoteCore{Core: c.Core.With(fields)}
+}
+
+func (c *demoteCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < zapcore.ErrorLevel {
+		ent.Level = zapcore.DebugLevel
+	}
+
+	return c.Core.Check(ent, ce)
+}
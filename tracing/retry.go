@@ -0,0 +1,25 @@
+package tracing
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jmanero/go-logging"
+)
+
+// RetryAttemptHeader is the request header read by the Attempt middleware
+const RetryAttemptHeader = "X-Retry-Attempt"
+
+// Attempt is a middleware function that annotates the request's context logger with the retry attempt number from an
+// X-Retry-Attempt header. Requests without a valid header are logged as attempt 0, the initial request
+func Attempt(next http.Handler) http.HandlerFunc {
+	return func(wr http.ResponseWriter, req *http.Request) {
+		n, err := strconv.Atoi(req.Header.Get(RetryAttemptHeader))
+		if err != nil || n < 0 {
+			n = 0
+		}
+
+		ctx, _ := logging.WithAttempt(req.Context(), n)
+		next.ServeHTTP(wr, req.WithContext(ctx))
+	}
+}
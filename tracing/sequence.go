@@ -0,0 +1,23 @@
+package tracing
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap"
+)
+
+// sequence is shared by every Sequence middleware in the process
+var sequence atomic.Int64
+
+// Sequence is a middleware function that annotates the request's context logger with a seq field, assigned from a
+// process-wide counter that increments for each request, to order requests within a single process instance and to
+// detect gaps in its logs. Numbers start at one when the process starts, and are shared by every server in the process
+// that uses Sequence. After math.MaxInt64 requests, the counter wraps to math.MinInt64 and continues to increment
+func Sequence(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+		ctx, _ := logging.With(req.Context(), zap.Int64("seq", sequence.Add(1)))
+		next.ServeHTTP(wr, req.WithContext(ctx))
+	})
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap"
+)
+
+// SpanIDHeader is the header used to propagate per-hop span identifiers
+const SpanIDHeader = "X-Span-ID"
+
+// Span is a middleware function that treats a RequestIDHeader as an end-to-end trace identifier, and generates a
+// new span identifier for each hop. The context logger is annotated with trace_id and span_id fields, and with a
+// parent_span_id field if the request carried an X-Span-ID header from the previous hop.
+//
+// Identifiers received from clients are sanitized with SanitizeID. Both identifiers are set on the request, for
+// propagation to upstream services, and on the response. The trace identifier is also available from RequestID, and
+// the span identifier from SpanID
+func Span(next http.Handler) http.HandlerFunc {
+	return span(next, defaultIdentifierOptions)
+}
+
+// NewSpan builds a Span middleware function with the same options as NewIdentifier: IdentifierWithHeader sets the
+// header that carries the trace identifier, IdentifierWithMaxLength limits both identifiers,
+// IdentifierWithResponseHeader controls whether they are returned to the client, and IdentifierWithFieldNames sets the
+// keys of the trace_id, span_id, and parent_span_id fields
+func NewSpan(opts ...IdentifierOption) func(http.Handler) http.Handler {
+	options := defaultIdentifierOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return span(next, options)
+	}
+}
+
+func span(next http.Handler, options identifierOptions) http.HandlerFunc {
+	return func(wr http.ResponseWriter, req *http.Request) {
+		traceID, valid := SanitizeID(req.Header.Get(options.header), options.maxLength)
+		if !valid {
+			var err error
+
+			traceID, err = GenerateID()
+			if err != nil {
+				panic(err)
+			}
+		}
+
+		req.Header.Set(options.header, traceID)
+
+		spanID, err := GenerateID()
+		if err != nil {
+			panic(err)
+		}
+
+		names := options.names
+		fields := []zap.Field{zap.String(names.TraceID, traceID), zap.String(names.SpanID, spanID)}
+		if parentID, valid := SanitizeID(req.Header.Get(SpanIDHeader), options.maxLength); valid {
+			fields = append(fields, zap.String(names.ParentSpanID, parentID))
+		}
+
+		// Replace the previous hop's span identifier for upstream requests
+		req.Header.Set(SpanIDHeader, spanID)
+
+		if options.response {
+			wr.Header().Set(options.header, traceID)
+			wr.Header().Set(SpanIDHeader, spanID)
+		}
+
+		ctx := context.WithValue(req.Context(), requestIDKey, requestID{header: options.header, id: traceID})
+		ctx, _ = logging.With(context.WithValue(ctx, spanIDKey, spanID), fields...)
+
+		next.ServeHTTP(wr, req.WithContext(ctx))
+	}
+}
+
+// SpanID retrieves the span identifier attached to a Context by the Span middleware
+func SpanID(ctx context.Context) (string, bool) {
+	id, is := ctx.Value(spanIDKey).(string)
+	return id, is
+}
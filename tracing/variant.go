@@ -0,0 +1,20 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/jmanero/go-logging"
+	"go.uber.org/zap"
+)
+
+// Variant is a middleware function that annotates the request's context logger with a variant field naming the
+// deployment variant that is serving it, e.g. "canary" or "blue". The name is typically read from the environment
+// when the server starts
+func Variant(name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(wr http.ResponseWriter, req *http.Request) {
+			ctx, _ := logging.With(req.Context(), zap.String("variant", name))
+			next.ServeHTTP(wr, req.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package logging
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogValidation is a helper to log a single info-level message to a Context logger with validation failures, as a
+// validation object that maps each failing field path (e.g. "address.zip") to its reason. Paths are logged in sorted
+// order. Validation failures are client errors, so they are not logged at a higher level. Nothing is logged if issues
+// is empty
+func LogValidation(ctx context.Context, msg string, issues map[string]string) {
+	if len(issues) == 0 {
+		return
+	}
+
+	FromContext(ctx).Info(msg, zap.Object("validation", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		for _, path := range slices.Sorted(maps.Keys(issues)) {
+			SafeString(path, issues[path]).AddTo(enc)
+		}
+
+		return nil
+	})))
+}
@@ -0,0 +1,158 @@
+package logging
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// WithAttempt adds an attempt number field to a Logger and re-injects it into a child Context. Attempts are counted
+// from 0 for the initial request, so that retries of a request share its identifier and increment the attempt field
+func WithAttempt(ctx context.Context, n int) (context.Context, *zap.Logger) {
+	return With(ctx, zap.Int("attempt", n))
+}
+
+// WithUser adds an authenticated user_id field to a Logger and re-injects it into a child Context.
+//
+// Authentication middleware should call WithUser and pass the returned Context to the next handler. The tracing.Logger
+// middleware derives its completion Logger before calling the next handler, so its completion entry only includes the
+// user_id field if the authentication middleware is wrapped outside of it, e.g.
+//
+//	handler = tracing.Logger(handler)
+//	handler = Authenticate(handler) // calls logging.WithUser(req.Context(), principal.ID)
+//
+// Entries logged by inner handlers include the field regardless of ordering
+func WithUser(ctx context.Context, userID string) (context.Context, *zap.Logger) {
+	return With(ctx, zap.String("user_id", userID))
+}
+
+// WithIDs adds a string field for each non-empty identifier in a map to a Logger, in key order, and re-injects it into
+// a child Context
+func WithIDs(ctx context.Context, ids map[string]string) (context.Context, *zap.Logger) {
+	keys := make([]string, 0, len(ids))
+	for key, id := range ids {
+		if len(id) > 0 {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	fields := make([]zap.Field, len(keys))
+	for i, key := range keys {
+		fields[i] = zap.String(key, ids[key])
+	}
+
+	return With(ctx, fields...)
+}
+
+// WithBuildInfo adds fields describing the running binary from debug.ReadBuildInfo to a Logger and re-injects it into a
+// child Context. go_version is always included, version is included when the main module has a version, and the
+// vcs_revision, vcs_time, and vcs_modified fields are included when the binary was built with VCS stamping
+func WithBuildInfo(ctx context.Context) (context.Context, *zap.Logger) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return With(ctx, zap.String("go_version", runtime.Version()))
+	}
+
+	fields := []zap.Field{zap.String("go_version", info.GoVersion)}
+	if len(info.Main.Version) > 0 && info.Main.Version != "(devel)" {
+		fields = append(fields, zap.String("version", info.Main.Version))
+	}
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			fields = append(fields, zap.String("vcs_revision", setting.Value))
+		case "vcs.time":
+			fields = append(fields, zap.String("vcs_time", setting.Value))
+		case "vcs.modified":
+			fields = append(fields, zap.Bool("vcs_modified", setting.Value == "true"))
+		}
+	}
+
+	return With(ctx, fields...)
+}
+
+// WithDeadline creates a child Context with a timeout, as context.WithTimeout, and adds the resulting deadline to its
+// Logger as a deadline field. A debug-level entry is logged with the timeout that was applied. The returned
+// CancelFunc must be called to release the Context's resources, as with context.WithTimeout
+func WithDeadline(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	deadline, _ := ctx.Deadline()
+
+	ctx, logger := With(ctx, zap.Time("deadline", deadline))
+	logger.Debug("deadline set", zap.Duration("timeout", d))
+
+	return ctx, cancel
+}
+
+// WithTxn adds a txn_id field to a Logger and re-injects it into a child Context, so that every entry logged within a
+// database transaction can be correlated, e.g.
+//
+//	ctx, logger := logging.WithTxn(ctx, txnID)
+//	logger.Debug("transaction started")
+//
+//	if err := work(ctx, tx); err != nil {
+//		logger.Warn("transaction rolled back", zap.Error(err))
+//		return tx.Rollback()
+//	}
+//
+//	logger.Debug("transaction committed")
+//	return tx.Commit()
+func WithTxn(ctx context.Context, txnID string) (context.Context, *zap.Logger) {
+	return With(ctx, zap.String("txn_id", txnID))
+}
+
+// WithStruct adds a field for each exported field of a struct, or pointer to a struct, to a Logger and re-injects it
+// into a child Context. Fields are named by a log:"name" tag, or by their Go name otherwise, and fields tagged with
+// log:"-" are skipped. Values are converted with zap.Any, so that they keep their types.
+//
+// Struct fields are enumerated with reflection on every call, which is considerably slower than constructing fields
+// directly, and zap.Any falls back to reflection for values without a dedicated field type
+func WithStruct(ctx context.Context, v any) (context.Context, *zap.Logger) {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return With(ctx)
+		}
+
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return With(ctx)
+	}
+
+	typ := val.Type()
+	fields := make([]zap.Field, 0, typ.NumField())
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, has := field.Tag.Lookup("log"); has {
+			tag, _, _ = strings.Cut(tag, ",")
+			if tag == "-" {
+				continue
+			}
+
+			if len(tag) > 0 {
+				name = tag
+			}
+		}
+
+		fields = append(fields, zap.Any(name, val.Field(i).Interface()))
+	}
+
+	return With(ctx, fields...)
+}